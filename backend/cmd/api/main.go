@@ -1,18 +1,27 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/ckinger23/mountaintop/internal/app"
 	"github.com/ckinger23/mountaintop/internal/database"
 	"github.com/ckinger23/mountaintop/internal/handlers"
 	"github.com/ckinger23/mountaintop/internal/middleware"
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/odds"
+	"github.com/ckinger23/mountaintop/internal/picks"
+	"github.com/ckinger23/mountaintop/internal/scheduler"
+	"github.com/ckinger23/mountaintop/internal/scores"
+	"github.com/ckinger23/mountaintop/internal/weeks"
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -48,6 +57,41 @@ func main() {
 	// Initialize application with dependencies
 	application := app.NewApp(db)
 
+	// Line imports read from an odds file when one is configured, e.g. ODDS_SOURCE=./data/lines.csv
+	if source := os.Getenv("ODDS_SOURCE"); source != "" {
+		application.Odds = odds.NewFileProvider(source)
+	}
+
+	// Background jobs
+	// Weeks past their pick deadline are locked automatically; the first run catches up after a restart
+	// Games members missed are filled in by their league's auto-pick policy as the week locks
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go scheduler.Every(ctx, time.Minute, "lock expired weeks", func(ctx context.Context) error {
+		_, err := weeks.LockExpired(db, time.Now(), func(tx *gorm.DB, week *models.Week, actor weeks.Actor) error {
+			_, err := picks.AutoPick(tx, week, actor)
+			return err
+		})
+		return err
+	})
+
+	// Live scores are polled when a scores feed is configured, e.g. SCORES_SOURCE=./data/scores.json
+	if source := os.Getenv("SCORES_SOURCE"); source != "" {
+		interval := time.Minute
+		if value := os.Getenv("SCORES_POLL_INTERVAL"); value != "" {
+			parsed, err := time.ParseDuration(value)
+			if err != nil {
+				log.Fatal("Invalid SCORES_POLL_INTERVAL:", err)
+			}
+			interval = parsed
+		}
+
+		poller := scores.NewPoller(db, scores.NewFixtureProvider(source))
+		go scheduler.Every(ctx, interval, "poll live scores", func(ctx context.Context) error {
+			return poller.Poll(ctx, time.Now())
+		})
+	}
+
 	// Initialize router
 	// returns a *chi.Mux which implements http.Handler
 	// define routes, URL params, add middleware (logging auth, recover)
@@ -91,6 +135,8 @@ func main() {
 		r.Get("/api/leagues", handlers.GetMyLeagues(application))
 		r.Get("/api/leagues/{id}", handlers.GetLeague(application))
 		r.Put("/api/leagues/{id}", handlers.UpdateLeague(application))
+		r.Get("/api/leagues/{id}/settings", handlers.GetLeagueSettings(application))
+		r.Put("/api/leagues/{id}/settings", handlers.UpdateLeagueSettings(application))
 		r.Delete("/api/leagues/{id}/leave", handlers.LeaveLeague(application))
 		r.Post("/api/leagues/join", handlers.JoinLeague(application))
 		r.Get("/api/leagues/browse", handlers.BrowsePublicLeagues(application))
@@ -98,15 +144,32 @@ func main() {
 		// Games
 		r.Get("/api/games", handlers.GetGames(application))
 		r.Get("/api/games/{id}", handlers.GetGame(application))
+		r.Get("/api/games/{id}/results", handlers.GetGameResults(application))
+		r.Get("/api/games/{id}/lines", handlers.GetGameLines(application))
 		r.Get("/api/weeks", handlers.GetWeeks(application))
 		r.Get("/api/weeks/current", handlers.GetCurrentWeek(application))
+		r.Get("/api/weeks/{id}/transitions", handlers.GetWeekTransitions(application))
+		r.Get("/api/schedule", handlers.GetScheduledGames(application))
 
 		// Picks
 		r.Post("/api/picks", handlers.SubmitPick(application))
+		r.Post("/api/picks/batch", handlers.SubmitPicksBatch(application))
 		r.Get("/api/picks/me", handlers.GetMyPicks(application))
+		r.Get("/api/picks/guesses/me", handlers.GetMyTiebreakerGuesses(application))
 		r.Get("/api/picks/user/{userId}", handlers.GetPicksForUser(application))
 		r.Get("/api/picks/week/{weekId}", handlers.GetAllPicksForWeek(application))
 		r.Get("/api/picks/stats/{userId}", handlers.GetPickStats(application))
+		r.Get("/api/leagues/{id}/picks/overrides", handlers.GetPickOverrides(application))
+		r.Get("/api/leagues/{id}/leaderboard/weekly", handlers.GetWeeklyLeaderboard(application))
+
+		// Survivor leagues
+		r.Post("/api/survivor/picks", handlers.SubmitSurvivorPick(application))
+		r.Get("/api/leagues/{id}/survivor", handlers.GetSurvivorStandings(application))
+
+		// Postseason brackets
+		r.Get("/api/weeks/{id}/bracket", handlers.GetBracket(application))
+		r.Post("/api/brackets/picks", handlers.SubmitBracket(application))
+		r.Get("/api/leagues/{id}/bracket", handlers.GetBracketStandings(application))
 	})
 
 	// Admin routes (authentication + admin required)
@@ -114,14 +177,25 @@ func main() {
 		r.Use(middleware.AuthMiddleware)
 		r.Use(middleware.AdminMiddleware)
 
+		// Commissioner pick overrides
+		r.Post("/api/admin/leagues/{id}/picks", handlers.OverridePick(application))
+
 		// Game management
 		r.Post("/api/admin/games", handlers.CreateGame(application))
 		r.Put("/api/admin/games/{id}", handlers.UpdateGame(application))
 		r.Delete("/api/admin/games/{id}", handlers.DeleteGame(application))
 		r.Put("/api/admin/games/{id}/result", handlers.UpdateGameResult(application))
+		r.Put("/api/admin/games/{id}/status", handlers.UpdateGameStatus(application))
+
+		// Master schedule (global admins) and league slates
+		r.Post("/api/admin/schedule", handlers.CreateScheduledGame(application))
+		r.Put("/api/admin/schedule/{id}/result", handlers.UpdateScheduledGameResult(application))
+		r.Put("/api/admin/schedule/{id}/status", handlers.UpdateScheduledGameStatus(application))
+		r.Post("/api/admin/weeks/{id}/slate", handlers.SelectWeekGames(application))
 
 		// Season management
 		r.Post("/api/admin/seasons", handlers.CreateSeason(application))
+		r.Post("/api/admin/seasons/{id}/schedule", handlers.ImportSeasonSchedule(application))
 
 		// Week management
 		r.Post("/api/admin/weeks", handlers.CreateWeek(application))
@@ -129,6 +203,11 @@ func main() {
 		r.Put("/api/admin/weeks/{id}/open", handlers.OpenWeekForPicks(application))
 		r.Put("/api/admin/weeks/{id}/lock", handlers.LockWeek(application))
 		r.Put("/api/admin/weeks/{id}/complete", handlers.CompleteWeek(application))
+		r.Put("/api/admin/weeks/{id}/tiebreaker", handlers.SetTiebreakerGame(application))
+		r.Post("/api/admin/weeks/{id}/bracket", handlers.CreateBracket(application))
+		r.Post("/api/admin/weeks/{id}/lines", handlers.ImportWeekLines(application))
+		r.Post("/api/admin/weeks/{id}/schedule", handlers.ImportWeekSchedule(application))
+		r.Get("/api/admin/weeks/{id}/picks/history", handlers.GetWeekPickHistory(application))
 	})
 
 	// Start server
@@ -0,0 +1,107 @@
+// Command import-schedule bulk-loads games from a CSV or JSON schedule file
+//
+// Usage:
+//
+//	go run ./cmd/import-schedule -season 1 -file schedule.csv [-week 3] [-dry-run]
+//
+// With -week every row goes into that week number; otherwise each row names its week.
+// Nothing is written if any row is invalid.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ckinger23/mountaintop/internal/database"
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/schedule"
+	"github.com/ckinger23/mountaintop/internal/teammatch"
+	"github.com/ckinger23/mountaintop/internal/weeks"
+	"gorm.io/gorm"
+)
+
+func main() {
+	defaultDB := os.Getenv("DB_PATH")
+	if defaultDB == "" {
+		defaultDB = "./cfb-picks.db"
+	}
+
+	dbPath := flag.String("db", defaultDB, "path to the SQLite database")
+	seasonID := flag.Uint("season", 0, "ID of the season to import into")
+	weekNumber := flag.Int("week", 0, "import every row into this week number")
+	file := flag.String("file", "", "schedule file (.csv or .json)")
+	dryRun := flag.Bool("dry-run", false, "validate the schedule without creating games")
+	flag.Parse()
+
+	if *seasonID == 0 || *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), ".")
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatal("Failed to open schedule:", err)
+	}
+	defer f.Close()
+
+	rows, err := schedule.Parse(format, f)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	db, err := database.Connect(*dbPath)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	var into *models.Week
+	if *weekNumber != 0 {
+		var week models.Week
+		if err := db.Where("season_id = ? AND week_number = ?", *seasonID, *weekNumber).First(&week).Error; err != nil {
+			log.Fatalf("Week %d not found in season %d", *weekNumber, *seasonID)
+		}
+		into = &week
+	}
+
+	resolver, err := teammatch.NewResolver(db)
+	if err != nil {
+		log.Fatal("Failed to load teams:", err)
+	}
+
+	games, rowErrors, err := schedule.Plan(db, uint(*seasonID), into, rows, resolver)
+	if err != nil {
+		log.Fatal("Failed to check schedule:", err)
+	}
+	if len(rowErrors) > 0 {
+		for _, rowErr := range rowErrors {
+			columns := make([]string, 0, len(rowErr.Details))
+			for column := range rowErr.Details {
+				columns = append(columns, column)
+			}
+			sort.Strings(columns)
+			for _, column := range columns {
+				fmt.Fprintf(os.Stderr, "row %d: %s: %s\n", rowErr.Row, column, rowErr.Details[column])
+			}
+		}
+		log.Fatalf("%d of %d rows are invalid; no games were created", len(rowErrors), len(rows))
+	}
+
+	if *dryRun {
+		fmt.Printf("Dry run: %d games would be created\n", len(games))
+		return
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		return schedule.Insert(tx, games, weeks.CLIActor("imported from "+filepath.Base(*file)))
+	})
+	if err != nil {
+		log.Fatal("Failed to create games:", err)
+	}
+	fmt.Printf("Created %d games\n", len(games))
+}
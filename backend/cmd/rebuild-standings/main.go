@@ -0,0 +1,40 @@
+// Command rebuild-standings recomputes the standings table from every pick
+//
+// Usage:
+//
+//	go run ./cmd/rebuild-standings [-db ./cfb-picks.db]
+//
+// Standings are kept up to date as picks are saved and graded; use this to repair them if they drift,
+// e.g. after picks are edited directly in the database. The table is replaced in a single transaction.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/ckinger23/mountaintop/internal/database"
+	"github.com/ckinger23/mountaintop/internal/leaderboard"
+)
+
+func main() {
+	defaultDB := os.Getenv("DB_PATH")
+	if defaultDB == "" {
+		defaultDB = "./cfb-picks.db"
+	}
+
+	dbPath := flag.String("db", defaultDB, "path to the SQLite database")
+	flag.Parse()
+
+	db, err := database.Connect(*dbPath)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	rows, err := leaderboard.RebuildStandings(db)
+	if err != nil {
+		log.Fatal("Failed to rebuild standings:", err)
+	}
+	fmt.Printf("Rebuilt %d standings rows\n", rows)
+}
@@ -1,12 +1,16 @@
 package app
 
 import (
+	"github.com/ckinger23/mountaintop/internal/odds"
 	"gorm.io/gorm"
 )
 
 // App holds all application dependencies
 type App struct {
 	DB *gorm.DB
+
+	// Odds is the sportsbook feed for line imports; nil when none is configured
+	Odds odds.Provider
 }
 
 // NewApp creates a new App instance with the provided dependencies
@@ -0,0 +1,255 @@
+package bracket
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"gorm.io/gorm"
+)
+
+// Entry is a user's bracket score for a postseason week
+type Entry struct {
+	UserID       uint   `json:"user_id"`
+	Username     string `json:"username"`
+	DisplayName  string `json:"display_name"`
+	TotalPoints  int    `json:"total_points"`
+	CorrectPicks int    `json:"correct_picks"`
+	GradedPicks  int    `json:"graded_picks"`
+}
+
+// PointsForRound returns the points for a correct pick in a round
+// Points double each round: 1, 2, 4, 8...
+func PointsForRound(round int) int {
+	if round < 1 {
+		return 0
+	}
+	return 1 << (round - 1)
+}
+
+// SortSlots orders slots by round, then position
+func SortSlots(slots []models.BracketSlot) {
+	sort.SliceStable(slots, func(i, j int) bool {
+		if slots[i].Round != slots[j].Round {
+			return slots[i].Round < slots[j].Round
+		}
+		return slots[i].Position < slots[j].Position
+	})
+}
+
+// ValidatePicks checks that a full bracket is consistent
+// picks maps slot ID to the picked team. Every slot must be picked, and a later-round pick
+// must be one of the teams the user advanced out of the feeding slots.
+// slots must have their Game preloaded. Returns field details keyed by slot, or nil if valid
+func ValidatePicks(slots []models.BracketSlot, picks map[uint]uint) map[string]string {
+	details := make(map[string]string)
+
+	ordered := make([]models.BracketSlot, len(slots))
+	copy(ordered, slots)
+	SortSlots(ordered)
+
+	known := make(map[uint]bool, len(slots))
+	for _, slot := range ordered {
+		known[slot.ID] = true
+	}
+	for slotID := range picks {
+		if !known[slotID] {
+			details[fmt.Sprintf("slot_%d", slotID)] = "Slot is not part of this bracket"
+		}
+	}
+
+	for _, slot := range ordered {
+		key := fmt.Sprintf("slot_%d", slot.ID)
+
+		teamID, ok := picks[slot.ID]
+		if !ok {
+			details[key] = "A pick is required for every bracket slot"
+			continue
+		}
+
+		allowed := allowedTeams(slot, picks)
+		if len(allowed) > 0 && !allowed[teamID] {
+			details[key] = "Picked team cannot reach this matchup in your bracket"
+		}
+	}
+
+	if len(details) > 0 {
+		return details
+	}
+	return nil
+}
+
+// allowedTeams returns the teams a user may pick for a slot given their other picks
+func allowedTeams(slot models.BracketSlot, picks map[uint]uint) map[uint]bool {
+	allowed := make(map[uint]bool)
+
+	if slot.HomeSourceSlotID != nil {
+		allowed[picks[*slot.HomeSourceSlotID]] = true
+	} else if slot.Game != nil {
+		allowed[slot.Game.HomeTeamID] = true
+	}
+
+	if slot.AwaySourceSlotID != nil {
+		allowed[picks[*slot.AwaySourceSlotID]] = true
+	} else if slot.Game != nil {
+		allowed[slot.Game.AwayTeamID] = true
+	}
+
+	delete(allowed, 0)
+	return allowed
+}
+
+// FirstKickoff returns the earliest kickoff among a bracket's slots
+// Brackets lock at this time. slots must have their Game preloaded
+func FirstKickoff(slots []models.BracketSlot) (first time.Time, ok bool) {
+	for _, slot := range slots {
+		kickoff := slot.GameTime
+		if slot.Game != nil {
+			kickoff = slot.Game.GameTime
+		}
+		if !ok || kickoff.Before(first) {
+			first = kickoff
+			ok = true
+		}
+	}
+	return first, ok
+}
+
+// AdvanceGame grades bracket picks for a final game and moves its winner into the next round
+// Games that aren't part of a bracket are ignored. This function should be called within a transaction
+func AdvanceGame(tx *gorm.DB, game *models.Game) error {
+	if !game.IsFinal {
+		return nil
+	}
+
+	var slot models.BracketSlot
+	err := tx.Where("game_id = ?", game.ID).First(&slot).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := gradeSlot(tx, slot, game); err != nil {
+		return err
+	}
+
+	// Fill in any slots fed by this one
+	var next []models.BracketSlot
+	if err := tx.Where("home_source_slot_id = ? OR away_source_slot_id = ?", slot.ID, slot.ID).Find(&next).Error; err != nil {
+		return err
+	}
+	for _, downstream := range next {
+		if err := fillSlot(tx, downstream); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// gradeSlot scores every bracket pick on a slot
+func gradeSlot(tx *gorm.DB, slot models.BracketSlot, game *models.Game) error {
+	var picks []models.BracketPick
+	if err := tx.Where("slot_id = ?", slot.ID).Find(&picks).Error; err != nil {
+		return err
+	}
+
+	for i := range picks {
+		picks[i].Outcome = models.PickOutcomeLoss
+		picks[i].PointsEarned = 0
+		if game.WinnerTeamID != nil && *game.WinnerTeamID == picks[i].PickedTeamID {
+			picks[i].Outcome = models.PickOutcomeWin
+			picks[i].PointsEarned = PointsForRound(slot.Round)
+		}
+
+		if err := tx.Save(&picks[i]).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fillSlot creates (or updates) a slot's game once both feeding slots have a winner
+func fillSlot(tx *gorm.DB, slot models.BracketSlot) error {
+	homeTeamID, err := sourceWinner(tx, slot.HomeSourceSlotID)
+	if err != nil || homeTeamID == nil {
+		return err
+	}
+	awayTeamID, err := sourceWinner(tx, slot.AwaySourceSlotID)
+	if err != nil || awayTeamID == nil {
+		return err
+	}
+
+	if slot.GameID == nil {
+		game := models.Game{
+			WeekID:     slot.WeekID,
+			HomeTeamID: *homeTeamID,
+			AwayTeamID: *awayTeamID,
+			GameTime:   slot.GameTime,
+		}
+		if err := tx.Create(&game).Error; err != nil {
+			return err
+		}
+		return tx.Model(&slot).Update("game_id", game.ID).Error
+	}
+
+	// A corrected result upstream can change the matchup until this game is played
+	var game models.Game
+	if err := tx.First(&game, *slot.GameID).Error; err != nil {
+		return err
+	}
+	if game.IsFinal {
+		return nil
+	}
+	game.HomeTeamID = *homeTeamID
+	game.AwayTeamID = *awayTeamID
+	return tx.Save(&game).Error
+}
+
+// sourceWinner returns the winner of a feeding slot, or nil if it isn't decided yet
+func sourceWinner(tx *gorm.DB, sourceSlotID *uint) (*uint, error) {
+	if sourceSlotID == nil {
+		return nil, nil
+	}
+
+	var source models.BracketSlot
+	if err := tx.Preload("Game").First(&source, *sourceSlotID).Error; err != nil {
+		return nil, err
+	}
+	if source.Game == nil || !source.Game.IsFinal {
+		return nil, nil
+	}
+	return source.Game.WinnerTeamID, nil
+}
+
+// Standings totals bracket points for every member of a league for a postseason week
+func Standings(db *gorm.DB, leagueID, weekID uint) ([]Entry, error) {
+	var entries []Entry
+
+	err := db.Table("league_memberships lm").
+		Select(`
+			u.id as user_id,
+			u.username,
+			u.display_name,
+			COALESCE(SUM(bp.points_earned), 0) as total_points,
+			COALESCE(SUM(CASE WHEN bp.outcome = 'win' THEN 1 ELSE 0 END), 0) as correct_picks,
+			COALESCE(SUM(CASE WHEN bp.outcome IN ('win', 'loss') THEN 1 ELSE 0 END), 0) as graded_picks
+		`).
+		Joins("JOIN users u ON u.id = lm.user_id").
+		Joins(`LEFT JOIN bracket_picks bp ON bp.user_id = lm.user_id AND bp.league_id = lm.league_id AND bp.deleted_at IS NULL
+			AND bp.slot_id IN (SELECT id FROM bracket_slots WHERE week_id = ? AND deleted_at IS NULL)`, weekID).
+		Where("lm.league_id = ? AND lm.deleted_at IS NULL", leagueID).
+		Group("u.id").
+		Order("total_points DESC, correct_picks DESC").
+		Scan(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,188 @@
+package bracket
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupTestDB creates an in-memory SQLite database for testing
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+
+	err = db.AutoMigrate(
+		&models.League{},
+		&models.LeagueMembership{},
+		&models.User{},
+		&models.Week{},
+		&models.Team{},
+		&models.Game{},
+		&models.BracketSlot{},
+		&models.BracketPick{},
+	)
+	if err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	return db
+}
+
+func uintPtr(v uint) *uint { return &v }
+
+// fourTeamBracket builds two semifinals feeding a championship (not persisted)
+// Semifinal 1: team 1 vs 2, semifinal 2: team 3 vs 4
+func fourTeamBracket() []models.BracketSlot {
+	return []models.BracketSlot{
+		{ID: 10, Round: 1, Position: 1, Game: &models.Game{HomeTeamID: 1, AwayTeamID: 2}},
+		{ID: 11, Round: 1, Position: 2, Game: &models.Game{HomeTeamID: 3, AwayTeamID: 4}},
+		{ID: 12, Round: 2, Position: 1, HomeSourceSlotID: uintPtr(10), AwaySourceSlotID: uintPtr(11)},
+	}
+}
+
+func TestPointsForRound(t *testing.T) {
+	assert.Equal(t, 0, PointsForRound(0))
+	assert.Equal(t, 1, PointsForRound(1))
+	assert.Equal(t, 2, PointsForRound(2))
+	assert.Equal(t, 4, PointsForRound(3))
+	assert.Equal(t, 8, PointsForRound(4))
+}
+
+func TestValidatePicks(t *testing.T) {
+	tests := []struct {
+		name      string
+		picks     map[uint]uint
+		wantError string // slot key expected in the details, empty for valid
+	}{
+		{"valid bracket", map[uint]uint{10: 1, 11: 4, 12: 4}, ""},
+		{"missing championship pick", map[uint]uint{10: 1, 11: 4}, "slot_12"},
+		{"first round team not in game", map[uint]uint{10: 3, 11: 4, 12: 4}, "slot_10"},
+		{"champion not advanced by user", map[uint]uint{10: 1, 11: 4, 12: 2}, "slot_12"},
+		{"unknown slot", map[uint]uint{10: 1, 11: 4, 12: 4, 99: 1}, "slot_99"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			details := ValidatePicks(fourTeamBracket(), tt.picks)
+			if tt.wantError == "" {
+				assert.Nil(t, details)
+				return
+			}
+			assert.Contains(t, details, tt.wantError)
+		})
+	}
+}
+
+func TestFirstKickoff(t *testing.T) {
+	early := time.Date(2025, 12, 20, 12, 0, 0, 0, time.UTC)
+	late := time.Date(2026, 1, 1, 17, 0, 0, 0, time.UTC)
+
+	slots := []models.BracketSlot{
+		{Game: &models.Game{GameTime: late}},
+		{Game: &models.Game{GameTime: early}},
+		{GameTime: late.AddDate(0, 0, 10)},
+	}
+
+	first, ok := FirstKickoff(slots)
+	assert.True(t, ok)
+	assert.Equal(t, early, first)
+
+	_, ok = FirstKickoff(nil)
+	assert.False(t, ok)
+}
+
+func TestAdvanceGame(t *testing.T) {
+	db := setupTestDB(t)
+
+	user := models.User{Username: "alice", Email: "alice@example.com", PasswordHash: "hash"}
+	db.Create(&user)
+
+	var teams []models.Team
+	for _, abbr := range []string{"TEA", "TEB", "TEC", "TED"} {
+		team := models.Team{Name: "Team " + abbr, Abbreviation: abbr}
+		db.Create(&team)
+		teams = append(teams, team)
+	}
+
+	week := models.Week{SeasonID: 1, WeekNumber: 16, Name: "Playoff", Status: "scoring", IsPostseason: true}
+	db.Create(&week)
+
+	semi1 := models.Game{WeekID: week.ID, HomeTeamID: teams[0].ID, AwayTeamID: teams[1].ID, GameTime: time.Now()}
+	semi2 := models.Game{WeekID: week.ID, HomeTeamID: teams[2].ID, AwayTeamID: teams[3].ID, GameTime: time.Now()}
+	db.Create(&semi1)
+	db.Create(&semi2)
+
+	slot1 := models.BracketSlot{WeekID: week.ID, Round: 1, Position: 1, GameID: &semi1.ID}
+	slot2 := models.BracketSlot{WeekID: week.ID, Round: 1, Position: 2, GameID: &semi2.ID}
+	db.Create(&slot1)
+	db.Create(&slot2)
+	final := models.BracketSlot{WeekID: week.ID, Round: 2, Position: 1, HomeSourceSlotID: &slot1.ID, AwaySourceSlotID: &slot2.ID, GameTime: time.Now().Add(24 * time.Hour)}
+	db.Create(&final)
+
+	// Alice picks team A and team D to meet, with team A winning it all
+	db.Create(&models.BracketPick{LeagueID: 1, UserID: user.ID, SlotID: slot1.ID, PickedTeamID: teams[0].ID})
+	db.Create(&models.BracketPick{LeagueID: 1, UserID: user.ID, SlotID: slot2.ID, PickedTeamID: teams[2].ID})
+	db.Create(&models.BracketPick{LeagueID: 1, UserID: user.ID, SlotID: final.ID, PickedTeamID: teams[0].ID})
+
+	finish := func(game *models.Game, homeScore, awayScore int, winner uint) {
+		game.HomeScore = &homeScore
+		game.AwayScore = &awayScore
+		game.WinnerTeamID = &winner
+		game.IsFinal = true
+		db.Save(game)
+		assert.NoError(t, AdvanceGame(db, game))
+	}
+
+	// First semifinal decided: championship still waiting on the other side
+	finish(&semi1, 31, 17, teams[0].ID)
+	db.First(&final, final.ID)
+	assert.Nil(t, final.GameID)
+
+	// Second semifinal decided: championship game is created with both winners
+	finish(&semi2, 10, 24, teams[3].ID)
+	db.First(&final, final.ID)
+	if assert.NotNil(t, final.GameID) {
+		var championship models.Game
+		db.First(&championship, *final.GameID)
+		assert.Equal(t, teams[0].ID, championship.HomeTeamID)
+		assert.Equal(t, teams[3].ID, championship.AwayTeamID)
+		assert.Equal(t, week.ID, championship.WeekID)
+
+		finish(&championship, 28, 21, teams[0].ID)
+	}
+
+	var picks []models.BracketPick
+	db.Order("slot_id ASC").Find(&picks)
+	assert.Equal(t, models.PickOutcomeWin, picks[0].Outcome)
+	assert.Equal(t, 1, picks[0].PointsEarned)
+	assert.Equal(t, models.PickOutcomeLoss, picks[1].Outcome)
+	assert.Equal(t, 0, picks[1].PointsEarned)
+	assert.Equal(t, models.PickOutcomeWin, picks[2].Outcome)
+	assert.Equal(t, 2, picks[2].PointsEarned) // Championship round is worth double
+
+	// Standings total the escalating points for league members
+	db.Create(&models.LeagueMembership{LeagueID: 1, UserID: user.ID, Role: "member", JoinedAt: time.Now()})
+	entries, err := Standings(db, 1, week.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, 3, entries[0].TotalPoints)
+		assert.Equal(t, 2, entries[0].CorrectPicks)
+		assert.Equal(t, 3, entries[0].GradedPicks)
+	}
+}
+
+func TestAdvanceGame_IgnoresNonBracketGames(t *testing.T) {
+	db := setupTestDB(t)
+
+	home, away := 21, 14
+	game := models.Game{WeekID: 1, HomeTeamID: 1, AwayTeamID: 2, IsFinal: true, HomeScore: &home, AwayScore: &away}
+	db.Create(&game)
+
+	assert.NoError(t, AdvanceGame(db, &game))
+}
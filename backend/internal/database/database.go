@@ -2,11 +2,13 @@ package database
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"time"
 
+	"github.com/ckinger23/mountaintop/internal/leaderboard"
 	"github.com/ckinger23/mountaintop/internal/models"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/driver/sqlite"
@@ -38,12 +40,24 @@ func Migrate(db *gorm.DB) error {
 	err := db.AutoMigrate(
 		&models.League{},           // NEW: Must come before User (foreign key)
 		&models.LeagueMembership{}, // NEW
+		&models.LeagueSettings{},
 		&models.User{},
 		&models.Season{},
 		&models.Week{},
+		&models.WeekTransition{},
 		&models.Team{},
+		&models.TeamAlias{},
+		&models.ScheduledGame{},
 		&models.Game{},
+		&models.GameResult{},
+		&models.GameLine{},
 		&models.Pick{},
+		&models.PickChange{},
+		&models.TiebreakerGuess{},
+		&models.Standing{},
+		&models.SurvivorPick{},
+		&models.BracketSlot{},
+		&models.BracketPick{},
 	)
 
 	if err != nil {
@@ -52,11 +66,92 @@ func Migrate(db *gorm.DB) error {
 
 	log.Println("Migrations completed successfully")
 
+	// Convert legacy boolean pick results into outcomes
+	if err := migratePickOutcomes(db); err != nil {
+		return fmt.Errorf("failed to migrate pick outcomes: %w", err)
+	}
+
+	// Games created before game statuses existed default to 'scheduled'
+	if err := db.Model(&models.Game{}).
+		Where("is_final = ? AND status = ?", true, models.GameStatusScheduled).
+		Update("status", models.GameStatusFinal).Error; err != nil {
+		return fmt.Errorf("failed to backfill game statuses: %w", err)
+	}
+
 	// After migrations, backfill existing data with default league
 	if err := backfillDefaultLeague(db); err != nil {
 		return fmt.Errorf("failed to backfill default league: %w", err)
 	}
 
+	// Databases seeded before team aliases existed have the teams but none of their aliases
+	if err := backfillTeamAliases(db); err != nil {
+		return fmt.Errorf("failed to backfill team aliases: %w", err)
+	}
+
+	// Databases created before the standings table have picks but no standings
+	if err := backfillStandings(db); err != nil {
+		return fmt.Errorf("failed to backfill standings: %w", err)
+	}
+
+	return nil
+}
+
+// backfillTeamAliases adds the aliases from teams.json that aren't in the database yet
+// Teams are matched by abbreviation; aliases already in use by any team are left alone, so it's safe to run every start
+func backfillTeamAliases(db *gorm.DB) error {
+	if _, err := os.Stat(teamsFile); os.IsNotExist(err) {
+		return nil
+	}
+	teams, err := loadTeams()
+	if err != nil {
+		return err
+	}
+
+	var added int
+	for _, team := range teams {
+		if len(team.Aliases) == 0 {
+			continue
+		}
+
+		var existing models.Team
+		if err := db.Where("abbreviation = ?", team.Abbreviation).First(&existing).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			return err
+		}
+
+		for _, alias := range team.Aliases {
+			row := models.TeamAlias{TeamID: existing.ID, Alias: alias.Alias}
+			result := db.Where(models.TeamAlias{Alias: alias.Alias}).Attrs(row).FirstOrCreate(&row)
+			if result.Error != nil {
+				return result.Error
+			}
+			added += int(result.RowsAffected)
+		}
+	}
+
+	if added > 0 {
+		log.Printf("Added %d team aliases", added)
+	}
+	return nil
+}
+
+// backfillStandings fills an empty standings table from the picks already made
+func backfillStandings(db *gorm.DB) error {
+	var standingCount, pickCount int64
+	db.Model(&models.Standing{}).Count(&standingCount)
+	db.Model(&models.Pick{}).Count(&pickCount)
+	if standingCount > 0 || pickCount == 0 {
+		return nil
+	}
+
+	log.Println("Building standings from existing picks...")
+	rows, err := leaderboard.RebuildStandings(db)
+	if err != nil {
+		return err
+	}
+	log.Printf("Built %d standings rows", rows)
 	return nil
 }
 
@@ -147,6 +242,82 @@ func backfillDefaultLeague(db *gorm.DB) error {
 	return nil
 }
 
+// migratePickOutcomes converts the legacy spread_correct/over_under_correct columns
+// into spread_outcome/over_under_outcome and drops the old columns
+func migratePickOutcomes(db *gorm.DB) error {
+	migrator := db.Migrator()
+
+	// Columns are dropped once converted, so this only runs on older databases
+	if !migrator.HasColumn(&models.Pick{}, "spread_correct") {
+		return nil
+	}
+
+	log.Println("Migrating pick results to outcomes...")
+
+	// A NULL result on a final game was a spread push, otherwise the pick is still pending
+	if err := db.Exec(`
+		UPDATE picks SET spread_outcome = CASE
+			WHEN spread_correct = 1 THEN 'win'
+			WHEN spread_correct = 0 THEN 'loss'
+			WHEN EXISTS (SELECT 1 FROM games g WHERE g.id = picks.game_id AND g.is_final = 1) THEN 'push'
+			ELSE ''
+		END
+		WHERE spread_outcome IS NULL OR spread_outcome = ''
+	`).Error; err != nil {
+		return fmt.Errorf("failed to migrate spread results: %w", err)
+	}
+
+	if err := db.Exec(`
+		UPDATE picks SET over_under_outcome = CASE
+			WHEN over_under_correct = 1 THEN 'win'
+			WHEN over_under_correct = 0 THEN 'loss'
+			ELSE ''
+		END
+		WHERE over_under_outcome IS NULL OR over_under_outcome = ''
+	`).Error; err != nil {
+		return fmt.Errorf("failed to migrate over/under results: %w", err)
+	}
+
+	// Pushes on the total were previously recorded as losses
+	if err := db.Exec(`
+		UPDATE picks SET over_under_outcome = 'push'
+		WHERE over_under_outcome = 'loss' AND EXISTS (
+			SELECT 1 FROM games g
+			WHERE g.id = picks.game_id AND g.is_final = 1
+			AND g.home_score + g.away_score = g.total
+		)
+	`).Error; err != nil {
+		return fmt.Errorf("failed to migrate over/under pushes: %w", err)
+	}
+
+	if err := migrator.DropColumn(&models.Pick{}, "spread_correct"); err != nil {
+		return fmt.Errorf("failed to drop spread_correct: %w", err)
+	}
+	if err := migrator.DropColumn(&models.Pick{}, "over_under_correct"); err != nil {
+		return fmt.Errorf("failed to drop over_under_correct: %w", err)
+	}
+
+	log.Println("Pick outcome migration completed")
+	return nil
+}
+
+// teamsFile lists every team with its abbreviation, conference and aliases
+const teamsFile = "data/teams.json"
+
+// loadTeams reads the teams, and their aliases, from teamsFile
+func loadTeams() ([]models.Team, error) {
+	data, err := os.ReadFile(teamsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read teams.json: %w", err)
+	}
+
+	var teams []models.Team
+	if err := json.Unmarshal(data, &teams); err != nil {
+		return nil, fmt.Errorf("failed to parse teams.json: %w", err)
+	}
+	return teams, nil
+}
+
 // SeedMode determines what data to seed
 type SeedMode string
 
@@ -169,14 +340,9 @@ func SeedData(db *gorm.DB, mode SeedMode) error {
 	}
 
 	// Load teams from JSON file
-	data, err := os.ReadFile("data/teams.json")
+	teams, err := loadTeams()
 	if err != nil {
-		return fmt.Errorf("failed to read teams.json: %w", err)
-	}
-
-	var teams []models.Team
-	if err := json.Unmarshal(data, &teams); err != nil {
-		return fmt.Errorf("failed to parse teams.json: %w", err)
+		return err
 	}
 
 	// Bulk insert all teams
@@ -0,0 +1,331 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ckinger23/mountaintop/internal/app"
+	"github.com/ckinger23/mountaintop/internal/bracket"
+	"github.com/ckinger23/mountaintop/internal/middleware"
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/validation"
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+// BracketSlotRequest describes one slot when building a bracket
+// First-round slots reference an existing game; later slots name the two earlier slots (by index) that feed them
+type BracketSlotRequest struct {
+	Name       string `json:"name"`
+	Round      int    `json:"round"`
+	Position   int    `json:"position"`
+	GameID     *uint  `json:"game_id"`     // Existing game with known teams
+	HomeSource *int   `json:"home_source"` // Index into slots of the slot whose winner is the home team
+	AwaySource *int   `json:"away_source"` // Index into slots of the slot whose winner is the away team
+	GameTime   string `json:"game_time"`   // ISO 8601 kickoff for slots without a game yet
+}
+
+type CreateBracketRequest struct {
+	Slots []BracketSlotRequest `json:"slots"`
+}
+
+type BracketPickRequest struct {
+	SlotID uint `json:"slot_id"`
+	TeamID uint `json:"team_id"`
+}
+
+type SubmitBracketRequest struct {
+	LeagueID uint                 `json:"league_id"`
+	WeekID   uint                 `json:"week_id"`
+	Picks    []BracketPickRequest `json:"picks"`
+}
+
+// CreateBracket returns a handler for building the bracket for a postseason week (admin only)
+func CreateBracket(a *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetUserFromContext(r)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		weekID := chi.URLParam(r, "id")
+
+		week, ok := verifyWeekPermission(a, w, claims, weekID)
+		if !ok {
+			return // error already sent by verifyWeekPermission
+		}
+
+		if !week.IsPostseason {
+			validation.RespondWithError(w, http.StatusBadRequest, "Week is not a postseason week", "NOT_POSTSEASON", nil)
+			return
+		}
+		if week.Status != "creating" {
+			validation.RespondWithError(w, http.StatusBadRequest, "Cannot edit bracket", "INVALID_STATUS", map[string]string{
+				"status": "Brackets can only be built while the week is in 'creating' status",
+			})
+			return
+		}
+
+		var existing int64
+		a.DB.Model(&models.BracketSlot{}).Where("week_id = ?", week.ID).Count(&existing)
+		if existing > 0 {
+			validation.RespondWithError(w, http.StatusConflict, "Bracket already exists for this week", "BRACKET_EXISTS", nil)
+			return
+		}
+
+		var req CreateBracketRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			validation.RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_JSON", nil)
+			return
+		}
+
+		// Validate slots and resolve kickoff times
+		details := make(map[string]string)
+		if len(req.Slots) == 0 {
+			details["slots"] = "At least one bracket slot is required"
+		}
+		kickoffs := make([]time.Time, len(req.Slots))
+		usedGames := make(map[uint]bool)
+		for i, s := range req.Slots {
+			key := fmt.Sprintf("slots[%d]", i)
+
+			if s.Round < 1 {
+				details[key] = "Round must be 1 or greater"
+				continue
+			}
+
+			if s.GameID != nil {
+				var game models.Game
+				if err := a.DB.Where("id = ? AND week_id = ?", *s.GameID, week.ID).First(&game).Error; err != nil {
+					details[key] = "Game must belong to this week"
+					continue
+				}
+				if usedGames[game.ID] {
+					details[key] = "Game is already used by another slot"
+					continue
+				}
+				usedGames[game.ID] = true
+				kickoffs[i] = game.GameTime
+				continue
+			}
+
+			if s.HomeSource == nil || s.AwaySource == nil {
+				details[key] = "Slot needs either a game_id or both home_source and away_source"
+				continue
+			}
+			for _, src := range []int{*s.HomeSource, *s.AwaySource} {
+				if src < 0 || src >= i || req.Slots[src].Round >= s.Round {
+					details[key] = "Sources must be earlier slots from an earlier round"
+				}
+			}
+
+			gameTime, valErr := validation.ValidateGameTime(s.GameTime)
+			if valErr != nil {
+				details[key] = valErr.Details["game_time"]
+				continue
+			}
+			kickoffs[i] = gameTime
+		}
+		if len(details) > 0 {
+			validation.RespondWithError(w, http.StatusBadRequest, "Validation failed", "VALIDATION_ERROR", details)
+			return
+		}
+
+		// Create slots in order so sources can be resolved to IDs
+		slots := make([]models.BracketSlot, len(req.Slots))
+		err := a.DB.Transaction(func(tx *gorm.DB) error {
+			for i, s := range req.Slots {
+				slots[i] = models.BracketSlot{
+					WeekID:   week.ID,
+					Round:    s.Round,
+					Position: s.Position,
+					Name:     s.Name,
+					GameTime: kickoffs[i],
+					GameID:   s.GameID,
+				}
+				if s.HomeSource != nil {
+					slots[i].HomeSourceSlotID = &slots[*s.HomeSource].ID
+				}
+				if s.AwaySource != nil {
+					slots[i].AwaySourceSlotID = &slots[*s.AwaySource].ID
+				}
+				if err := tx.Create(&slots[i]).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			validation.RespondWithError(w, http.StatusInternalServerError, "Error creating bracket", "DATABASE_ERROR", nil)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(slots)
+	}
+}
+
+// GetBracket returns a handler for fetching a postseason week's bracket ordered by round
+func GetBracket(a *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		weekID := chi.URLParam(r, "id")
+
+		var slots []models.BracketSlot
+		if err := a.DB.Where("week_id = ?", weekID).
+			Preload("Game.HomeTeam").
+			Preload("Game.AwayTeam").
+			Find(&slots).Error; err != nil {
+			http.Error(w, "Error fetching bracket", http.StatusInternalServerError)
+			return
+		}
+		bracket.SortSlots(slots)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(slots)
+	}
+}
+
+// SubmitBracket returns a handler for submitting a user's full bracket
+// The whole bracket is replaced at once and locks at the first postseason kickoff
+func SubmitBracket(a *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetUserFromContext(r)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var req SubmitBracketRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			validation.RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_JSON", nil)
+			return
+		}
+
+		// Check that the week exists, belongs to this league, and is a postseason week
+		var week models.Week
+		if err := a.DB.Preload("Season").First(&week, req.WeekID).Error; err != nil || week.Season.LeagueID != req.LeagueID {
+			validation.RespondWithError(w, http.StatusNotFound, "Week not found", "WEEK_NOT_FOUND", nil)
+			return
+		}
+		if !week.IsPostseason {
+			validation.RespondWithError(w, http.StatusBadRequest, "Week is not a postseason week", "NOT_POSTSEASON", nil)
+			return
+		}
+		if week.Status != "picking" {
+			validation.RespondWithError(w, http.StatusForbidden, "Picks are not open for this week", "WEEK_NOT_OPEN", nil)
+			return
+		}
+
+		// Verify user is a member of this league
+		var membership models.LeagueMembership
+		if err := a.DB.Where("league_id = ? AND user_id = ?", req.LeagueID, claims.UserID).First(&membership).Error; err != nil {
+			validation.RespondWithError(w, http.StatusForbidden, "You are not a member of this league", "FORBIDDEN", nil)
+			return
+		}
+
+		var slots []models.BracketSlot
+		if err := a.DB.Where("week_id = ?", week.ID).Preload("Game").Find(&slots).Error; err != nil {
+			validation.RespondWithError(w, http.StatusInternalServerError, "Error fetching bracket", "DATABASE_ERROR", nil)
+			return
+		}
+		if len(slots) == 0 {
+			validation.RespondWithError(w, http.StatusBadRequest, "This week has no bracket", "NO_BRACKET", nil)
+			return
+		}
+
+		// Brackets lock at the first kickoff
+		if firstKickoff, ok := bracket.FirstKickoff(slots); ok && time.Now().After(firstKickoff) {
+			validation.RespondWithError(w, http.StatusForbidden, "Brackets are locked once the first game kicks off", "BRACKET_LOCKED", nil)
+			return
+		}
+
+		picks := make(map[uint]uint, len(req.Picks))
+		for _, p := range req.Picks {
+			picks[p.SlotID] = p.TeamID
+		}
+		if details := bracket.ValidatePicks(slots, picks); details != nil {
+			validation.RespondWithError(w, http.StatusBadRequest, "Invalid bracket", "VALIDATION_ERROR", details)
+			return
+		}
+
+		// Replace any earlier bracket in one transaction
+		slotIDs := make([]uint, len(slots))
+		for i, slot := range slots {
+			slotIDs[i] = slot.ID
+		}
+		var saved []models.BracketPick
+		err := a.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Unscoped().
+				Where("league_id = ? AND user_id = ? AND slot_id IN ?", req.LeagueID, claims.UserID, slotIDs).
+				Delete(&models.BracketPick{}).Error; err != nil {
+				return err
+			}
+
+			for _, slot := range slots {
+				pick := models.BracketPick{
+					LeagueID:     req.LeagueID,
+					UserID:       claims.UserID,
+					SlotID:       slot.ID,
+					PickedTeamID: picks[slot.ID],
+				}
+				if err := tx.Create(&pick).Error; err != nil {
+					return err
+				}
+				saved = append(saved, pick)
+			}
+			return nil
+		})
+		if err != nil {
+			validation.RespondWithError(w, http.StatusInternalServerError, "Error saving bracket", "DATABASE_ERROR", nil)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(saved)
+	}
+}
+
+// GetBracketStandings returns a handler for a league's bracket scores for a postseason week
+func GetBracketStandings(a *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetUserFromContext(r)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		leagueID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+		if err != nil {
+			http.Error(w, "Invalid league ID", http.StatusBadRequest)
+			return
+		}
+
+		weekID, err := strconv.ParseUint(r.URL.Query().Get("week_id"), 10, 32)
+		if err != nil {
+			http.Error(w, "Invalid week_id parameter", http.StatusBadRequest)
+			return
+		}
+
+		// Verify user is a member of this league
+		var membership models.LeagueMembership
+		if err := a.DB.Where("league_id = ? AND user_id = ?", leagueID, claims.UserID).First(&membership).Error; err != nil && !claims.IsGlobalAdmin {
+			http.Error(w, "You are not a member of this league", http.StatusForbidden)
+			return
+		}
+
+		entries, err := bracket.Standings(a.DB, uint(leagueID), uint(weekID))
+		if err != nil {
+			http.Error(w, "Error fetching bracket standings", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(entries)
+	}
+}
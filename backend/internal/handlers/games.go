@@ -2,14 +2,20 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/ckinger23/mountaintop/internal/app"
 	"github.com/ckinger23/mountaintop/internal/leaderboard"
 	"github.com/ckinger23/mountaintop/internal/middleware"
 	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/odds"
+	"github.com/ckinger23/mountaintop/internal/results"
+	"github.com/ckinger23/mountaintop/internal/scoring"
 	"github.com/ckinger23/mountaintop/internal/validation"
+	"github.com/ckinger23/mountaintop/internal/weeks"
 	"github.com/go-chi/chi/v5"
 	"gorm.io/gorm"
 )
@@ -77,6 +83,7 @@ type CreateGameRequest struct {
 	GameTime   string  `json:"game_time"` // ISO 8601 format
 	HomeSpread float64 `json:"home_spread"`
 	Total      float64 `json:"total"`
+	ExternalID string  `json:"external_id"` // Optional: the scores feed's ID for this game
 }
 
 // CreateGame returns a handler for creating a new game (admin only)
@@ -144,9 +151,17 @@ func CreateGame(a *app.App) http.HandlerFunc {
 			GameTime:   gameTime,
 			HomeSpread: req.HomeSpread,
 			Total:      req.Total,
+			ExternalID: req.ExternalID,
 		}
 
-		if err := a.DB.Create(&game).Error; err != nil {
+		// The opening line starts the game's line history
+		err := a.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&game).Error; err != nil {
+				return err
+			}
+			return odds.RecordLine(tx, game.ID, game.HomeSpread, game.Total, weeks.UserActor(claims.UserID))
+		})
+		if err != nil {
 			validation.RespondWithError(w, http.StatusInternalServerError, "Error creating game", "DATABASE_ERROR", nil)
 			return
 		}
@@ -226,6 +241,9 @@ func UpdateGame(a *app.App) http.HandlerFunc {
 			return
 		}
 
+		lineMoved := game.HomeSpread != req.HomeSpread || game.Total != req.Total
+		fromWeekID := game.WeekID
+
 		// Update game fields
 		game.WeekID = req.WeekID
 		game.HomeTeamID = req.HomeTeamID
@@ -233,8 +251,28 @@ func UpdateGame(a *app.App) http.HandlerFunc {
 		game.GameTime = gameTime
 		game.HomeSpread = req.HomeSpread
 		game.Total = req.Total
+		game.ExternalID = req.ExternalID
 
-		if err := a.DB.Save(&game).Error; err != nil {
+		// Existing picks keep the line they were made on; the move is added to the line history
+		err := a.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Save(&game).Error; err != nil {
+				return err
+			}
+			// Picks on a game moved to another week count toward that week
+			if game.WeekID != fromWeekID {
+				if err := leaderboard.RefreshWeek(tx, fromWeekID); err != nil {
+					return err
+				}
+				if err := leaderboard.RefreshWeek(tx, game.WeekID); err != nil {
+					return err
+				}
+			}
+			if !lineMoved {
+				return nil
+			}
+			return odds.RecordLine(tx, game.ID, game.HomeSpread, game.Total, weeks.UserActor(claims.UserID))
+		})
+		if err != nil {
 			validation.RespondWithError(w, http.StatusInternalServerError, "Error updating game", "DATABASE_ERROR", nil)
 			return
 		}
@@ -297,12 +335,23 @@ func DeleteGame(a *app.App) http.HandlerFunc {
 }
 
 type UpdateGameResultRequest struct {
-	HomeScore int  `json:"home_score"`
-	AwayScore int  `json:"away_score"`
-	IsFinal   bool `json:"is_final"`
+	HomeScore int    `json:"home_score"`
+	AwayScore int    `json:"away_score"`
+	IsFinal   bool   `json:"is_final"`
+	Override  bool   `json:"override"` // Required to change a result after the week is finished
+	Reason    string `json:"reason"`   // Optional note kept in the game's result history
+}
+
+// GameResultResponse is the updated game along with what the result changed
+type GameResultResponse struct {
+	models.Game
+	IsCorrection       bool                        `json:"is_correction"`
+	WeekReopened       bool                        `json:"week_reopened"`
+	LeaderboardChanges []results.LeaderboardChange `json:"leaderboard_changes,omitempty"`
 }
 
 // UpdateGameResult returns a handler for updating the score and determining winner (admin only)
+// Changing a final score is recorded as a correction and rescores every affected pick
 func UpdateGameResult(a *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		claims, ok := middleware.GetUserFromContext(r)
@@ -320,9 +369,7 @@ func UpdateGameResult(a *app.App) http.HandlerFunc {
 		}
 
 		// Validate scores
-		homeScore := &req.HomeScore
-		awayScore := &req.AwayScore
-		if valErr := validation.ValidateUpdateGameResult(homeScore, awayScore, req.IsFinal); valErr != nil {
+		if valErr := validation.ValidateUpdateGameResult(&req.HomeScore, &req.AwayScore, req.IsFinal); valErr != nil {
 			validation.RespondWithValidationError(w, valErr)
 			return
 		}
@@ -340,54 +387,154 @@ func UpdateGameResult(a *app.App) http.HandlerFunc {
 			return
 		}
 
-		// Start transaction for updating game and calculating picks
-		tx := a.DB.Begin()
-		if tx.Error != nil {
-			validation.RespondWithError(w, http.StatusInternalServerError, "Error starting transaction", "DATABASE_ERROR", nil)
+		// Games from the master schedule get their result there, so every league stays in step
+		if game.ScheduledGameID != nil {
+			validation.RespondWithError(w, http.StatusConflict, "This game's result comes from the master schedule", "SHARED_GAME", map[string]string{
+				"scheduled_game_id": "Enter the result on the master schedule game instead",
+			})
 			return
 		}
 
-		// Ensure rollback on panic or error
-		defer func() {
-			if r := recover(); r != nil {
-				tx.Rollback()
-				validation.RespondWithError(w, http.StatusInternalServerError, "Internal server error", "PANIC", nil)
-			}
-		}()
-
-		// Update game result
-		game.HomeScore = homeScore
-		game.AwayScore = awayScore
-		game.IsFinal = req.IsFinal
-
-		// Determine winner
-		if req.HomeScore > req.AwayScore {
-			game.WinnerTeamID = &game.HomeTeamID
-		} else if req.AwayScore > req.HomeScore {
-			game.WinnerTeamID = &game.AwayTeamID
-		} else {
-			// If tied, WinnerTeamID remains nil
-			game.WinnerTeamID = nil
+		actor := weeks.UserActor(claims.UserID)
+		actor.Reason = req.Reason
+		submission := results.Submission{
+			HomeScore: req.HomeScore,
+			AwayScore: req.AwayScore,
+			IsFinal:   req.IsFinal,
+			Override:  req.Override,
 		}
 
-		if err := tx.Save(&game).Error; err != nil {
-			tx.Rollback()
-			validation.RespondWithError(w, http.StatusInternalServerError, "Error updating game", "DATABASE_ERROR", nil)
+		// Update the game and its picks together so the leaderboard never sees a half-scored game
+		var outcome *results.Outcome
+		err := a.DB.Transaction(func(tx *gorm.DB) error {
+			var err error
+			outcome, err = results.Record(tx, &game, submission, actor)
+			return err
+		})
+		if errors.Is(err, results.ErrGameNotPlayed) {
+			validation.RespondWithError(w, http.StatusConflict, "Game is postponed or cancelled", "GAME_NOT_PLAYED", map[string]string{
+				"status": "Set the game back to scheduled before entering a result",
+			})
+			return
+		}
+		if errors.Is(err, results.ErrWeekFinished) {
+			validation.RespondWithError(w, http.StatusConflict, "This week is finished", "WEEK_FINISHED", map[string]string{
+				"override": "Set override to correct a result after the week is finished",
+			})
+			return
+		}
+		if err != nil {
+			validation.RespondWithError(w, http.StatusInternalServerError, "Error calculating pick results", "SCORING_ERROR", nil)
 			return
 		}
 
-		// If game is final, calculate pick results within the same transaction
-		if game.IsFinal {
-			if err := calculatePickResults(tx, game.ID); err != nil {
-				tx.Rollback()
-				validation.RespondWithError(w, http.StatusInternalServerError, "Error calculating pick results", "SCORING_ERROR", nil)
+		// Reload game with relationships after successful commit
+		a.DB.Preload("HomeTeam").Preload("AwayTeam").Preload("Week").First(&game, game.ID)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(GameResultResponse{
+			Game:               game,
+			IsCorrection:       outcome.Correction,
+			WeekReopened:       outcome.WeekReopened,
+			LeaderboardChanges: outcome.LeaderboardChanges,
+		})
+	}
+}
+
+type UpdateGameStatusRequest struct {
+	Status   string `json:"status"`    // scheduled, in_progress, postponed or cancelled
+	WeekID   *uint  `json:"week_id"`   // Postponed games only: the week the game moves to
+	GameTime string `json:"game_time"` // Required with week_id: the new kickoff (ISO 8601 format)
+}
+
+// UpdateGameStatus returns a handler for postponing, cancelling or restarting a game (admin only)
+// Cancelling voids the game's picks. A postponed game given a week_id is moved there and scheduled again;
+// this is the only change allowed on a master schedule game's league copy
+func UpdateGameStatus(a *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetUserFromContext(r)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		gameID := chi.URLParam(r, "id")
+
+		var game models.Game
+		if err := a.DB.Preload("Week.Season.League").First(&game, gameID).Error; err != nil {
+			validation.RespondWithError(w, http.StatusNotFound, "Game not found", "GAME_NOT_FOUND", nil)
+			return
+		}
+
+		// Verify user has permission to manage this league
+		if !canManageLeague(claims, game.Week.Season.League.OwnerID) {
+			validation.RespondWithError(w, http.StatusForbidden, "You don't have permission to manage this league", "FORBIDDEN", nil)
+			return
+		}
+
+		var req UpdateGameStatusRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			validation.RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_JSON", nil)
+			return
+		}
+
+		// Games from the master schedule change status there, so every league stays in step.
+		// Once postponed, each league picks the week its copy moves to
+		moving := req.Status == models.GameStatusPostponed && req.WeekID != nil && game.Status == models.GameStatusPostponed
+		if game.ScheduledGameID != nil && !moving {
+			validation.RespondWithError(w, http.StatusConflict, "This game's status comes from the master schedule", "SHARED_GAME", map[string]string{
+				"scheduled_game_id": "Change the status on the master schedule game instead; postponed games can still be moved to another week",
+			})
+			return
+		}
+
+		// Check the week a postponed game is moving to
+		var target *models.Week
+		var gameTime time.Time
+		if req.WeekID != nil {
+			if req.Status != models.GameStatusPostponed {
+				validation.RespondWithError(w, http.StatusBadRequest, "Only postponed games can move to another week", "VALIDATION_ERROR", map[string]string{
+					"week_id": "week_id can only be set when postponing a game",
+				})
+				return
+			}
+
+			var valErr *validation.ValidationError
+			gameTime, valErr = validation.ValidateGameTime(req.GameTime)
+			if valErr != nil {
+				validation.RespondWithValidationError(w, valErr)
 				return
 			}
+
+			var week models.Week
+			if err := a.DB.Preload("Season").First(&week, *req.WeekID).Error; err != nil || week.Season.LeagueID != game.Week.Season.LeagueID {
+				validation.RespondWithError(w, http.StatusBadRequest, "Week not found", "WEEK_NOT_FOUND", map[string]string{
+					"week_id": "The specified week does not exist in this league",
+				})
+				return
+			}
+			if week.ID == game.WeekID || (week.Status != "creating" && week.Status != "picking") {
+				validation.RespondWithError(w, http.StatusBadRequest, "Cannot move game to this week", "WEEK_NOT_OPEN", map[string]string{
+					"week_id": "Postponed games can only move to another week that hasn't locked",
+				})
+				return
+			}
+			target = &week
 		}
 
-		// Commit transaction
-		if err := tx.Commit().Error; err != nil {
-			validation.RespondWithError(w, http.StatusInternalServerError, "Error committing transaction", "DATABASE_ERROR", nil)
+		err := a.DB.Transaction(func(tx *gorm.DB) error {
+			if req.Status == models.GameStatusPostponed {
+				return results.Postpone(tx, &game, target, gameTime)
+			}
+			return results.SetStatus(tx, &game, req.Status)
+		})
+		var valErr *validation.ValidationError
+		if errors.As(err, &valErr) {
+			validation.RespondWithValidationError(w, valErr)
+			return
+		}
+		if err != nil {
+			validation.RespondWithError(w, http.StatusInternalServerError, "Error updating game status", "DATABASE_ERROR", nil)
 			return
 		}
 
@@ -399,61 +546,58 @@ func UpdateGameResult(a *app.App) http.HandlerFunc {
 	}
 }
 
-// calculatePickResults updates all picks for a game once it's final
-// This function should be called within a transaction
-func calculatePickResults(tx *gorm.DB, gameID uint) error {
-	var game models.Game
-	if err := tx.First(&game, gameID).Error; err != nil {
-		return err
-	}
-
-	// Verify game has scores and winner
-	if game.HomeScore == nil || game.AwayScore == nil {
-		return nil // Nothing to calculate without scores
-	}
+// GetGameResults returns a handler for every score submitted for a game, oldest first
+func GetGameResults(a *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		gameID := chi.URLParam(r, "id")
 
-	var picks []models.Pick
-	if err := tx.Where("game_id = ?", gameID).Find(&picks).Error; err != nil {
-		return err
-	}
+		var history []models.GameResult
+		if err := a.DB.Where("game_id = ?", gameID).
+			Preload("User").
+			Order("created_at ASC, id ASC").
+			Find(&history).Error; err != nil {
+			http.Error(w, "Error fetching result history", http.StatusInternalServerError)
+			return
+		}
 
-	// If no picks, nothing to do
-	if len(picks) == 0 {
-		return nil
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(history)
 	}
+}
 
-	// Calculate actual total score
-	actualTotal := float64(*game.HomeScore + *game.AwayScore)
+// GetGameLines returns a handler for fetching a game's line history, oldest first
+func GetGameLines(a *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		gameID := chi.URLParam(r, "id")
 
-	// Update all picks in a batch
-	for i := range picks {
-		// Check spread pick correctness
-		spreadCorrect := false
-		if game.WinnerTeamID != nil {
-			spreadCorrect = picks[i].PickedTeamID == *game.WinnerTeamID
+		var history []models.GameLine
+		if err := a.DB.Where("game_id = ?", gameID).
+			Preload("User").
+			Order("created_at ASC, id ASC").
+			Find(&history).Error; err != nil {
+			http.Error(w, "Error fetching line history", http.StatusInternalServerError)
+			return
 		}
-		picks[i].SpreadCorrect = &spreadCorrect
 
-		// Check over/under pick correctness
-		var overUnderCorrect bool
-		if picks[i].PickedOverUnder == "over" {
-			overUnderCorrect = actualTotal > game.Total
-		} else if picks[i].PickedOverUnder == "under" {
-			overUnderCorrect = actualTotal < game.Total
-		}
-		picks[i].OverUnderCorrect = &overUnderCorrect
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(history)
+	}
+}
 
-		// Scoring: 1 point for correct spread, 1 point for correct over/under (max 2 points per game)
-		picks[i].PointsEarned = 0
-		if spreadCorrect {
-			picks[i].PointsEarned++
-		}
-		if overUnderCorrect {
-			picks[i].PointsEarned++
-		}
+// rescoreLeague recomputes pick results for every final game a league has picks on
+// Used after a league's scoring rules change. This function should be called within a transaction
+func rescoreLeague(tx *gorm.DB, leagueID uint) error {
+	var gameIDs []uint
+	if err := tx.Model(&models.Pick{}).
+		Joins("JOIN games ON games.id = picks.game_id").
+		Where("picks.league_id = ? AND games.is_final = ?", leagueID, true).
+		Distinct().
+		Pluck("picks.game_id", &gameIDs).Error; err != nil {
+		return err
+	}
 
-		// Save each pick within the transaction
-		if err := tx.Save(&picks[i]).Error; err != nil {
+	for _, gameID := range gameIDs {
+		if err := results.ScorePicks(tx, gameID); err != nil {
 			return err
 		}
 	}
@@ -532,7 +676,7 @@ func GetSeasons(a *app.App) http.HandlerFunc {
 	}
 }
 
-// GetLeaderboard returns a handler for fetching the current standings
+// GetLeaderboard returns a handler for fetching the current standings, read from the standings table
 func GetLeaderboard(a *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Get season Id from query param
@@ -561,7 +705,24 @@ func GetLeaderboard(a *app.App) http.HandlerFunc {
 			leagueID = &uid
 		}
 
-		entries, err := leaderboard.GetLeaderboard(a.DB, seasonID, leagueID)
+		query := leaderboard.NewQuery(a.DB)
+		if seasonID != nil {
+			query = query.ForSeason(*seasonID)
+		}
+		if leagueID != nil {
+			// Ties are broken and ranked the way the league is set up
+			settings, err := scoring.LoadSettings(a.DB, *leagueID)
+			if err != nil {
+				http.Error(w, "Error loading league settings", http.StatusInternalServerError)
+				return
+			}
+			query = query.ForLeague(*leagueID).
+				WithTiebreakers(validation.SplitList(settings.Tiebreakers)).
+				WithRanking(settings.RankingStyle).
+				WithGuessRule(settings.GuessRule)
+		}
+
+		entries, err := query.Execute()
 		if err != nil {
 			http.Error(w, "Error fetching leaderboard", http.StatusInternalServerError)
 			return
@@ -571,3 +732,55 @@ func GetLeaderboard(a *app.App) http.HandlerFunc {
 		json.NewEncoder(w).Encode(entries)
 	}
 }
+
+// GetWeeklyLeaderboard returns a handler for a league's member x week points grid and each week's winners (members only)
+// Defaults to the league's active season, or its most recent one; pass ?season_id= for another
+func GetWeeklyLeaderboard(a *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetUserFromContext(r)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		leagueID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+		if err != nil {
+			http.Error(w, "Invalid league ID", http.StatusBadRequest)
+			return
+		}
+
+		// Verify user is a member of this league
+		var membership models.LeagueMembership
+		if err := a.DB.Where("league_id = ? AND user_id = ?", leagueID, claims.UserID).First(&membership).Error; err != nil && !claims.IsGlobalAdmin {
+			http.Error(w, "You are not a member of this league", http.StatusForbidden)
+			return
+		}
+
+		query := a.DB.Where("league_id = ?", leagueID)
+		if seasonID := r.URL.Query().Get("season_id"); seasonID != "" {
+			query = query.Where("id = ?", seasonID)
+		} else {
+			query = query.Order("is_active DESC, year DESC, id DESC")
+		}
+		var season models.Season
+		if err := query.First(&season).Error; err != nil {
+			validation.RespondWithError(w, http.StatusNotFound, "Season not found in this league", "SEASON_NOT_FOUND", nil)
+			return
+		}
+
+		settings, err := scoring.LoadSettings(a.DB, uint(leagueID))
+		if err != nil {
+			http.Error(w, "Error loading league settings", http.StatusInternalServerError)
+			return
+		}
+
+		grid, err := leaderboard.WeeklyGrid(a.DB, uint(leagueID), season.ID, validation.SplitList(settings.Tiebreakers), settings.GuessRule)
+		if err != nil {
+			http.Error(w, "Error fetching weekly leaderboard", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(grid)
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ckinger23/mountaintop/internal/app"
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/results"
+	"github.com/ckinger23/mountaintop/internal/weeks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// withID makes a handler see id as its {id} URL parameter
+func withID(handler http.HandlerFunc, id uint) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", fmt.Sprint(id))
+		handler(w, r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx)))
+	}
+}
+
+func TestUpdateGameStatus_MovePostponedSharedGame(t *testing.T) {
+	db := setupTestDB(t)
+	owner := models.User{Username: "owner", Email: "owner@example.com", PasswordHash: "hash"}
+	db.Create(&owner)
+	league := models.League{Name: "Shared", Code: "SHARE-1", OwnerID: owner.ID, IsActive: true}
+	db.Create(&league)
+	db.Create(&models.LeagueSettings{LeagueID: league.ID, Format: models.LeagueFormatStandard, PostponedPicks: models.PostponedPicksKeep})
+	season := models.Season{LeagueID: league.ID, Year: 2025}
+	db.Create(&season)
+	week1 := models.Week{SeasonID: season.ID, WeekNumber: 1, Name: "Week 1", Status: "picking"}
+	week2 := models.Week{SeasonID: season.ID, WeekNumber: 2, Name: "Week 2", Status: "picking"}
+	db.Create(&week1)
+	db.Create(&week2)
+	teams := []models.Team{{Name: "A", Abbreviation: "A"}, {Name: "B", Abbreviation: "B"}}
+	db.Create(&teams)
+
+	kickoff := time.Now().Add(24 * time.Hour)
+	sg := models.ScheduledGame{Year: 2025, WeekNumber: 1, HomeTeamID: teams[0].ID, AwayTeamID: teams[1].ID, GameTime: kickoff}
+	db.Create(&sg)
+	game := models.Game{WeekID: week1.ID, HomeTeamID: teams[0].ID, AwayTeamID: teams[1].ID, GameTime: kickoff, ScheduledGameID: &sg.ID}
+	db.Create(&game)
+
+	handler := withID(UpdateGameStatus(app.NewApp(db)), game.ID)
+	move := UpdateGameStatusRequest{Status: models.GameStatusPostponed, WeekID: &week2.ID, GameTime: kickoff.AddDate(0, 0, 7).Format(time.RFC3339)}
+
+	// The league can't postpone its copy on its own
+	rec := serveAs(handler, owner.ID, http.MethodPut, move)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	// Once the master game is postponed, the league moves its copy to week 2
+	assert.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+		_, err := results.SetScheduledStatus(tx, &sg, models.GameStatusPostponed)
+		return err
+	}))
+	rec = serveAs(handler, owner.ID, http.MethodPut, move)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	db.First(&game, game.ID)
+	assert.Equal(t, week2.ID, game.WeekID)
+	assert.Equal(t, models.GameStatusScheduled, game.Status)
+
+	// The result is recorded on the moved copy once the master game is back on
+	assert.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+		if _, err := results.SetScheduledStatus(tx, &sg, models.GameStatusScheduled); err != nil {
+			return err
+		}
+		_, err := results.RecordScheduled(tx, &sg, results.Submission{HomeScore: 21, AwayScore: 14, IsFinal: true}, weeks.UserActor(owner.ID))
+		return err
+	}))
+	db.First(&game, game.ID)
+	assert.True(t, game.IsFinal)
+	assert.Equal(t, week2.ID, game.WeekID)
+}
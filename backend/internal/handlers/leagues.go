@@ -13,7 +13,10 @@ import (
 	"github.com/ckinger23/mountaintop/internal/app"
 	"github.com/ckinger23/mountaintop/internal/middleware"
 	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/scoring"
+	"github.com/ckinger23/mountaintop/internal/validation"
 	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
 )
 
 // CreateLeagueRequest is the request body for creating a league
@@ -209,6 +212,155 @@ func UpdateLeague(a *app.App) http.HandlerFunc {
 	}
 }
 
+// UpdateLeagueSettingsRequest is the request body for changing league rules
+// Omitted fields keep their current value
+type UpdateLeagueSettingsRequest struct {
+	Format           *string `json:"format"`
+	SpreadPoints     *int    `json:"spread_points"`
+	OverUnderPoints  *int    `json:"over_under_points"`
+	PushPoints       *int    `json:"push_points"`
+	UpsetBonusPoints *int    `json:"upset_bonus_points"`
+	OverUnderEnabled *bool   `json:"over_under_enabled"`
+	LockAtKickoff    *bool   `json:"lock_at_kickoff"`
+	PostponedPicks   *string `json:"postponed_picks"`
+	AutoPick         *string `json:"auto_pick"`
+	Tiebreakers      *string `json:"tiebreakers"` // Comma-separated, e.g. "spread_wins,correct_picks"
+	RankingStyle     *string `json:"ranking_style"`
+	GuessRule        *string `json:"guess_rule"` // without_going_over or closest
+}
+
+// GetLeagueSettings returns a league's scoring rules (members only)
+func GetLeagueSettings(a *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetUserFromContext(r)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		leagueID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+		if err != nil {
+			http.Error(w, "Invalid league ID", http.StatusBadRequest)
+			return
+		}
+
+		// Verify user is a member of this league
+		var membership models.LeagueMembership
+		if err := a.DB.Where("league_id = ? AND user_id = ?", leagueID, claims.UserID).First(&membership).Error; err != nil && !claims.IsGlobalAdmin {
+			http.Error(w, "You are not a member of this league", http.StatusForbidden)
+			return
+		}
+
+		settings, err := scoring.LoadSettings(a.DB, uint(leagueID))
+		if err != nil {
+			http.Error(w, "Failed to fetch league settings", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(settings)
+	}
+}
+
+// UpdateLeagueSettings changes a league's scoring rules and rescores its final games (owner only)
+func UpdateLeagueSettings(a *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetUserFromContext(r)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		leagueID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+		if err != nil {
+			http.Error(w, "Invalid league ID", http.StatusBadRequest)
+			return
+		}
+
+		var league models.League
+		if err := a.DB.First(&league, leagueID).Error; err != nil {
+			validation.RespondWithError(w, http.StatusNotFound, "League not found", "LEAGUE_NOT_FOUND", nil)
+			return
+		}
+
+		// Verify user has permission to manage this league
+		if !canManageLeague(claims, league.OwnerID) {
+			validation.RespondWithError(w, http.StatusForbidden, "You don't have permission to manage this league", "FORBIDDEN", nil)
+			return
+		}
+
+		var req UpdateLeagueSettingsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			validation.RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_JSON", nil)
+			return
+		}
+
+		settings, err := scoring.LoadSettings(a.DB, league.ID)
+		if err != nil {
+			validation.RespondWithError(w, http.StatusInternalServerError, "Error loading league settings", "DATABASE_ERROR", nil)
+			return
+		}
+
+		// Apply provided fields
+		if req.Format != nil {
+			settings.Format = *req.Format
+		}
+		if req.SpreadPoints != nil {
+			settings.SpreadPoints = *req.SpreadPoints
+		}
+		if req.OverUnderPoints != nil {
+			settings.OverUnderPoints = *req.OverUnderPoints
+		}
+		if req.PushPoints != nil {
+			settings.PushPoints = *req.PushPoints
+		}
+		if req.UpsetBonusPoints != nil {
+			settings.UpsetBonusPoints = *req.UpsetBonusPoints
+		}
+		if req.OverUnderEnabled != nil {
+			settings.OverUnderEnabled = *req.OverUnderEnabled
+		}
+		if req.LockAtKickoff != nil {
+			settings.LockAtKickoff = *req.LockAtKickoff
+		}
+		if req.PostponedPicks != nil {
+			settings.PostponedPicks = *req.PostponedPicks
+		}
+		if req.AutoPick != nil {
+			settings.AutoPick = *req.AutoPick
+		}
+		if req.Tiebreakers != nil {
+			settings.Tiebreakers = *req.Tiebreakers
+		}
+		if req.RankingStyle != nil {
+			settings.RankingStyle = *req.RankingStyle
+		}
+		if req.GuessRule != nil {
+			settings.GuessRule = *req.GuessRule
+		}
+
+		if valErr := validation.ValidateLeagueSettings(settings.Format, settings.PostponedPicks, settings.AutoPick, settings.Tiebreakers, settings.RankingStyle, settings.GuessRule, settings.SpreadPoints, settings.OverUnderPoints, settings.PushPoints, settings.UpsetBonusPoints); valErr != nil {
+			validation.RespondWithValidationError(w, valErr)
+			return
+		}
+
+		// Save settings and rescore existing results together so the leaderboard never mixes rules
+		err = a.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Save(&settings).Error; err != nil {
+				return err
+			}
+			return rescoreLeague(tx, league.ID)
+		})
+		if err != nil {
+			validation.RespondWithError(w, http.StatusInternalServerError, "Error updating league settings", "DATABASE_ERROR", nil)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(settings)
+	}
+}
+
 // JoinLeague allows a user to join a league by code
 func JoinLeague(a *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
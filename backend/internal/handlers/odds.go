@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ckinger23/mountaintop/internal/app"
+	"github.com/ckinger23/mountaintop/internal/middleware"
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/odds"
+	"github.com/ckinger23/mountaintop/internal/teammatch"
+	"github.com/ckinger23/mountaintop/internal/validation"
+	"github.com/ckinger23/mountaintop/internal/weeks"
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+// ImportLinesResponse reports how a week's lines compare to the odds feed
+type ImportLinesResponse struct {
+	odds.Report
+	Applied bool `json:"applied"`
+}
+
+// ImportWeekLines pulls lines from the odds feed for every game in a week
+// By default it only previews the changes; pass ?apply=true to save them
+func ImportWeekLines(a *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetUserFromContext(r)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		if a.Odds == nil {
+			validation.RespondWithError(w, http.StatusServiceUnavailable, "No odds feed is configured", "ODDS_NOT_CONFIGURED", nil)
+			return
+		}
+
+		weekID := chi.URLParam(r, "id")
+
+		week, ok := verifyWeekPermission(a, w, claims, weekID)
+		if !ok {
+			return // error already sent by verifyWeekPermission
+		}
+
+		if week.Status != "creating" && week.Status != "picking" {
+			validation.RespondWithError(w, http.StatusBadRequest, "Cannot import lines", "INVALID_STATUS", map[string]string{
+				"status": "Lines can only be imported while the week is in 'creating' or 'picking' status",
+			})
+			return
+		}
+
+		lines, err := a.Odds.Lines(r.Context())
+		if err != nil {
+			validation.RespondWithError(w, http.StatusBadGateway, "Error fetching lines from odds feed", "ODDS_FEED_ERROR", map[string]string{
+				"feed": err.Error(),
+			})
+			return
+		}
+
+		var games []models.Game
+		if err := a.DB.Preload("HomeTeam").Preload("AwayTeam").Where("week_id = ?", week.ID).Order("game_time").Find(&games).Error; err != nil {
+			http.Error(w, "Error fetching games", http.StatusInternalServerError)
+			return
+		}
+
+		resolver, err := teammatch.NewResolver(a.DB)
+		if err != nil {
+			http.Error(w, "Error fetching teams", http.StatusInternalServerError)
+			return
+		}
+
+		response := ImportLinesResponse{Report: odds.Compare(games, lines, resolver)}
+		response.Provider = a.Odds.Name()
+
+		if r.URL.Query().Get("apply") == "true" {
+			err := a.DB.Transaction(func(tx *gorm.DB) error {
+				actor := weeks.UserActor(claims.UserID)
+				actor.Reason = "imported from " + a.Odds.Name()
+				return odds.Apply(tx, response.Report, actor)
+			})
+			if err != nil {
+				validation.RespondWithError(w, http.StatusInternalServerError, "Error saving lines", "DATABASE_ERROR", nil)
+				return
+			}
+			response.Applied = true
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}
+}
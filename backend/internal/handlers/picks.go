@@ -2,13 +2,21 @@ package handlers
 
 import (
 	"encoding/json"
+	"net"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/ckinger23/mountaintop/internal/app"
+	"github.com/ckinger23/mountaintop/internal/leaderboard"
 	"github.com/ckinger23/mountaintop/internal/middleware"
 	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/picks"
+	"github.com/ckinger23/mountaintop/internal/scoring"
+	"github.com/ckinger23/mountaintop/internal/validation"
+	"github.com/ckinger23/mountaintop/internal/weeks"
 	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
 )
 
 type SubmitPickRequest struct {
@@ -17,6 +25,23 @@ type SubmitPickRequest struct {
 	PickedTeamID    uint   `json:"picked_team_id"`
 	PickedOverUnder string `json:"picked_over_under"` // "over" or "under"
 	Confidence      int    `json:"confidence"`
+	// Also save the pick in every other league the user is in that has the same game
+	AllLeagues bool `json:"all_leagues"`
+}
+
+// SubmitPickResponse is the saved pick along with how it went in the user's other leagues
+type SubmitPickResponse struct {
+	Pick    *models.Pick         `json:"pick"`
+	Leagues []picks.LeagueResult `json:"leagues"`
+}
+
+// pickAudit returns the audit details for a pick change made by the request's user
+func pickAudit(r *http.Request, userID uint) picks.Audit {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	return picks.Audit{Actor: weeks.UserActor(userID), IP: ip}
 }
 
 // SubmitPick returns a handler for creating or updating a user's pick for a game
@@ -34,92 +59,222 @@ func SubmitPick(a *app.App) http.HandlerFunc {
 			return
 		}
 
-		// Get the game to check week status and pick deadline
+		// Get the game to check week status and pick deadline; it must be on this league's slate
 		var game models.Game
-		if err := a.DB.Preload("Week").First(&game, req.GameID).Error; err != nil {
-			http.Error(w, "Game not found", http.StatusNotFound)
+		if err := a.DB.Preload("Week.Season").First(&game, req.GameID).Error; err != nil || game.Week.Season.LeagueID != req.LeagueID {
+			validation.RespondWithError(w, http.StatusNotFound, "Game not found in this league", "GAME_NOT_FOUND", nil)
 			return
 		}
 
-		// Check if week is in 'picking' status
-		if game.Week.Status != "picking" {
-			http.Error(w, "Picks are not open for this week", http.StatusForbidden)
+		// Verify user is a member of this league
+		var membership models.LeagueMembership
+		if err := a.DB.Where("league_id = ? AND user_id = ?", req.LeagueID, claims.UserID).First(&membership).Error; err != nil {
+			http.Error(w, "You are not a member of this league", http.StatusForbidden)
 			return
 		}
 
-		// Check if pick deadline has passed
-		if game.Week.PickDeadline != nil && time.Now().After(*game.Week.PickDeadline) {
-			http.Error(w, "Pick deadline has passed for this week", http.StatusForbidden)
+		settings, err := scoring.LoadSettings(a.DB, req.LeagueID)
+		if err != nil {
+			http.Error(w, "Error loading league settings", http.StatusInternalServerError)
 			return
 		}
 
-		// Check if game is final
-		if game.IsFinal {
-			http.Error(w, "Cannot pick a game that is final", http.StatusForbidden)
+		entry := picks.Entry{
+			GameID:          req.GameID,
+			PickedTeamID:    req.PickedTeamID,
+			PickedOverUnder: req.PickedOverUnder,
+			Confidence:      req.Confidence,
+		}
+
+		// Check week status, team, over/under and lock
+		if problem := picks.Check(&game, entry, settings, time.Now()); problem != nil {
+			status := http.StatusForbidden
+			if problem.Code == picks.CodeInvalidTeam || problem.Code == picks.CodeInvalidOverUnder {
+				status = http.StatusBadRequest
+			}
+			http.Error(w, problem.Message, status)
 			return
 		}
 
-		// Validate that picked team is in the game
-		if req.PickedTeamID != game.HomeTeamID && req.PickedTeamID != game.AwayTeamID {
-			http.Error(w, "Invalid team selection for this game", http.StatusBadRequest)
+		// Confidence pools require a unique 1..N value per game within the week
+		if settings.Format == models.LeagueFormatConfidence {
+			problems, err := picks.CheckConfidence(a.DB, req.LeagueID, claims.UserID, game.WeekID, []picks.Entry{entry})
+			if err != nil {
+				http.Error(w, "Error checking confidence values", http.StatusInternalServerError)
+				return
+			}
+			if len(problems) > 0 {
+				validation.RespondWithError(w, http.StatusBadRequest, "Validation failed", "VALIDATION_ERROR", map[string]string{
+					"confidence": problems[0].Message,
+				})
+				return
+			}
+		}
+
+		var pick *models.Pick
+		var created bool
+		err = a.DB.Transaction(func(tx *gorm.DB) error {
+			var err error
+			if pick, created, err = picks.Save(tx, req.LeagueID, claims.UserID, &game, entry, pickAudit(r, claims.UserID)); err != nil {
+				return err
+			}
+			return leaderboard.RefreshMembers(tx, req.LeagueID, game.WeekID, claims.UserID)
+		})
+		if err != nil {
+			http.Error(w, "Error saving pick", http.StatusInternalServerError)
 			return
 		}
 
-		// Validate over/under pick
-		if req.PickedOverUnder != "over" && req.PickedOverUnder != "under" {
-			http.Error(w, "Must pick 'over' or 'under' for total", http.StatusBadRequest)
+		// Load relationships
+		a.DB.Preload("Game").Preload("PickedTeam").First(pick, pick.ID)
+
+		if !req.AllLeagues {
+			w.Header().Set("Content-Type", "application/json")
+			if created {
+				w.WriteHeader(http.StatusCreated)
+			}
+			json.NewEncoder(w).Encode(pick)
 			return
 		}
 
+		// Copy the pick to the same game in the user's other leagues; each league accepts or rejects it on its own
+		others, err := picks.OtherLeagueGames(a.DB, &game, req.LeagueID, claims.UserID)
+		if err != nil {
+			http.Error(w, "Error finding other leagues", http.StatusInternalServerError)
+			return
+		}
+		results, err := picks.SaveToLeagues(a.DB, others, claims.UserID, entry, pickAudit(r, claims.UserID), time.Now())
+		if err != nil {
+			http.Error(w, "Error saving pick to other leagues", http.StatusInternalServerError)
+			return
+		}
 
-	// Verify user is a member of this league
-	var membership models.LeagueMembership
-	if err := a.DB.Where("league_id = ? AND user_id = ?", req.LeagueID, claims.UserID).First(&membership).Error; err != nil {
-		http.Error(w, "You are not a member of this league", http.StatusForbidden)
-		return
+		w.Header().Set("Content-Type", "application/json")
+		if created {
+			w.WriteHeader(http.StatusCreated)
+		}
+		json.NewEncoder(w).Encode(SubmitPickResponse{Pick: pick, Leagues: results})
 	}
-		// Check if pick already exists
-		var existingPick models.Pick
-		err := a.DB.Where("league_id = ? AND user_id = ? AND game_id = ?", req.LeagueID, claims.UserID, req.GameID).First(&existingPick).Error
-
-		if err == nil {
-			// Update existing pick
-			existingPick.PickedTeamID = req.PickedTeamID
-			existingPick.PickedOverUnder = req.PickedOverUnder
-			existingPick.Confidence = req.Confidence
-
-			if err := a.DB.Save(&existingPick).Error; err != nil {
-				http.Error(w, "Error updating pick", http.StatusInternalServerError)
-				return
-			}
+}
 
-			a.DB.Preload("Game").Preload("PickedTeam").First(&existingPick, existingPick.ID)
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(existingPick)
+type SubmitPicksBatchRequest struct {
+	LeagueID uint          `json:"league_id"`
+	WeekID   uint          `json:"week_id"`
+	Picks    []picks.Entry `json:"picks"`
+	// Guess at the combined score of the week's tiebreaker game, optional
+	TotalPointsGuess *int `json:"total_points_guess"`
+}
+
+// PickProblemsResponse reports the games that stopped a batch of picks from being saved
+type PickProblemsResponse struct {
+	validation.ErrorResponse
+	Games []picks.Problem `json:"games"`
+}
+
+// SubmitPicksBatch returns a handler for submitting a user's picks for a whole week at once
+// Every pick is validated first and they're saved together, so a bad pick never leaves a half-submitted card
+func SubmitPicksBatch(a *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetUserFromContext(r)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
 
-		// Create new pick
-		pick := models.Pick{
-		LeagueID:        req.LeagueID,
-			UserID:          claims.UserID,
-			GameID:          req.GameID,
-			PickedTeamID:    req.PickedTeamID,
-			PickedOverUnder: req.PickedOverUnder,
-			Confidence:      req.Confidence,
+		var req SubmitPicksBatchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			validation.RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_JSON", nil)
+			return
+		}
+		if len(req.Picks) == 0 {
+			validation.RespondWithError(w, http.StatusBadRequest, "Validation failed", "VALIDATION_ERROR", map[string]string{
+				"picks": "At least one pick is required",
+			})
+			return
+		}
+
+		// Verify user is a member of this league
+		var membership models.LeagueMembership
+		if err := a.DB.Where("league_id = ? AND user_id = ?", req.LeagueID, claims.UserID).First(&membership).Error; err != nil {
+			validation.RespondWithError(w, http.StatusForbidden, "You are not a member of this league", "NOT_MEMBER", nil)
+			return
+		}
+
+		var week models.Week
+		if err := a.DB.Preload("Season").First(&week, req.WeekID).Error; err != nil || week.Season.LeagueID != req.LeagueID {
+			validation.RespondWithError(w, http.StatusNotFound, "Week not found in this league", "WEEK_NOT_FOUND", nil)
+			return
 		}
 
-		if err := a.DB.Create(&pick).Error; err != nil {
-			http.Error(w, "Error creating pick", http.StatusInternalServerError)
+		settings, err := scoring.LoadSettings(a.DB, req.LeagueID)
+		if err != nil {
+			http.Error(w, "Error loading league settings", http.StatusInternalServerError)
+			return
+		}
+
+		// Check and save in one transaction so the week can't lock between the two
+		now := time.Now()
+		audit := pickAudit(r, claims.UserID)
+		var problems []picks.Problem
+		saved := make([]models.Pick, 0, len(req.Picks))
+		err = a.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.First(&week, week.ID).Error; err != nil {
+				return err
+			}
+
+			games, weekProblems, err := picks.CheckWeek(tx, &week, req.LeagueID, claims.UserID, settings, req.Picks, now)
+			if err != nil {
+				return err
+			}
+			problems = weekProblems
+			if req.TotalPointsGuess != nil {
+				problem, err := picks.CheckGuess(tx, &week, *req.TotalPointsGuess, settings, now)
+				if err != nil {
+					return err
+				}
+				if problem != nil {
+					problems = append(problems, *problem)
+				}
+			}
+			if len(problems) > 0 {
+				return nil
+			}
+
+			for _, entry := range req.Picks {
+				pick, _, err := picks.Save(tx, req.LeagueID, claims.UserID, games[entry.GameID], entry, audit)
+				if err != nil {
+					return err
+				}
+				saved = append(saved, *pick)
+			}
+			if req.TotalPointsGuess != nil {
+				if _, err := picks.SaveGuess(tx, req.LeagueID, claims.UserID, week.ID, *req.TotalPointsGuess); err != nil {
+					return err
+				}
+			}
+			return leaderboard.RefreshMembers(tx, req.LeagueID, week.ID, claims.UserID)
+		})
+		if err != nil {
+			http.Error(w, "Error saving picks", http.StatusInternalServerError)
+			return
+		}
+		if len(problems) > 0 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(PickProblemsResponse{
+				ErrorResponse: validation.ErrorResponse{Error: "Some picks can't be saved", Code: "VALIDATION_ERROR"},
+				Games:         problems,
+			})
 			return
 		}
 
 		// Load relationships
-		a.DB.Preload("Game").Preload("PickedTeam").First(&pick, pick.ID)
+		for i := range saved {
+			a.DB.Preload("Game").Preload("PickedTeam").First(&saved[i], saved[i].ID)
+		}
 
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusCreated)
-		json.NewEncoder(w).Encode(pick)
+		json.NewEncoder(w).Encode(saved)
 	}
 }
 
@@ -162,6 +317,34 @@ func GetMyPicks(a *app.App) http.HandlerFunc {
 	}
 }
 
+// GetMyTiebreakerGuesses returns a handler for fetching the authenticated user's total-points guesses
+func GetMyTiebreakerGuesses(a *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetUserFromContext(r)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		query := a.DB.Where("user_id = ?", claims.UserID)
+		if leagueID := r.URL.Query().Get("league_id"); leagueID != "" {
+			query = query.Where("league_id = ?", leagueID)
+		}
+		if weekID := r.URL.Query().Get("week_id"); weekID != "" {
+			query = query.Where("week_id = ?", weekID)
+		}
+
+		var guesses []models.TiebreakerGuess
+		if err := query.Order("week_id").Find(&guesses).Error; err != nil {
+			http.Error(w, "Error fetching guesses", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(guesses)
+	}
+}
+
 // GetPicksForUser returns a handler for fetching all picks for a specific user (viewable by anyone)
 func GetPicksForUser(a *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -192,10 +375,22 @@ func GetPicksForUser(a *app.App) http.HandlerFunc {
 			return
 		}
 
-		// Only show picks if games have started or are final
+		// Only show picks once they've locked or their game has kicked off
+		lockAtKickoff := make(map[uint]bool)
+		now := time.Now()
 		var visiblePicks []models.Pick
 		for _, pick := range picks {
-			if pick.Game.IsFinal || time.Now().After(pick.Game.GameTime) {
+			kickoffLocking, loaded := lockAtKickoff[pick.LeagueID]
+			if !loaded {
+				settings, err := scoring.LoadSettings(a.DB, pick.LeagueID)
+				if err != nil {
+					http.Error(w, "Error loading league settings", http.StatusInternalServerError)
+					return
+				}
+				kickoffLocking = settings.LockAtKickoff
+				lockAtKickoff[pick.LeagueID] = kickoffLocking
+			}
+			if weeks.PickVisible(&pick.Game.Week, &pick.Game, kickoffLocking, now) {
 				visiblePicks = append(visiblePicks, pick)
 			}
 		}
@@ -212,19 +407,23 @@ func GetAllPicksForWeek(a *app.App) http.HandlerFunc {
 
 		// Get the week to check status
 		var week models.Week
-		if err := a.DB.First(&week, weekID).Error; err != nil {
+		if err := a.DB.Preload("Season").First(&week, weekID).Error; err != nil {
 			http.Error(w, "Week not found", http.StatusNotFound)
 			return
 		}
 
-		// Check if week is locked (in scoring or finished status)
-		if week.Status == "creating" || week.Status == "picking" {
-			// Only admins can view before week is locked
-			claims, ok := middleware.GetUserFromContext(r)
-			if !ok || !claims.IsAdmin {
-				http.Error(w, "Picks not yet visible", http.StatusForbidden)
-				return
-			}
+		// Only admins can view picks before the week opens
+		claims, ok := middleware.GetUserFromContext(r)
+		isAdmin := ok && claims.IsAdmin
+		if week.Status == "creating" && !isAdmin {
+			http.Error(w, "Picks not yet visible", http.StatusForbidden)
+			return
+		}
+
+		settings, err := scoring.LoadSettings(a.DB, week.Season.LeagueID)
+		if err != nil {
+			http.Error(w, "Error loading league settings", http.StatusInternalServerError)
+			return
 		}
 
 		var picks []models.Pick
@@ -240,6 +439,18 @@ func GetAllPicksForWeek(a *app.App) http.HandlerFunc {
 			return
 		}
 
+		// While picking is open, non-admins only see picks on games that have locked
+		if !isAdmin {
+			now := time.Now()
+			visiblePicks := []models.Pick{}
+			for _, pick := range picks {
+				if weeks.PickVisible(&week, &pick.Game, settings.LockAtKickoff, now) {
+					visiblePicks = append(visiblePicks, pick)
+				}
+			}
+			picks = visiblePicks
+		}
+
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(picks)
 	}
@@ -254,24 +465,40 @@ func GetPickStats(a *app.App) http.HandlerFunc {
 			TotalPicks     int64   `json:"total_picks"`
 			CorrectPicks   int64   `json:"correct_picks"`
 			IncorrectPicks int64   `json:"incorrect_picks"`
+			PushPicks      int64   `json:"push_picks"`
 			WinPercentage  float64 `json:"win_percentage"`
 			TotalPoints    int     `json:"total_points"`
 		}
 
 		var stats Stats
 
+		// Graded picks are those with a spread outcome (voided picks don't count)
 		a.DB.Model(&models.Pick{}).
-			Where("user_id = ? AND is_correct IS NOT NULL", userID).
+			Where("user_id = ? AND spread_outcome IN ?", userID, []models.PickOutcome{models.PickOutcomeWin, models.PickOutcomeLoss, models.PickOutcomePush}).
 			Count(&stats.TotalPicks)
 
+		// Wins, losses and pushes are counted per component (spread + over/under)
+		var counts struct {
+			Wins   int64
+			Losses int64
+			Pushes int64
+		}
 		a.DB.Model(&models.Pick{}).
-			Where("user_id = ? AND is_correct = ?", userID, true).
-			Count(&stats.CorrectPicks)
-
-		stats.IncorrectPicks = stats.TotalPicks - stats.CorrectPicks
-
-		if stats.TotalPicks > 0 {
-			stats.WinPercentage = float64(stats.CorrectPicks) / float64(stats.TotalPicks) * 100
+			Where("user_id = ?", userID).
+			Select(`
+				COALESCE(SUM(CASE WHEN spread_outcome = 'win' THEN 1 ELSE 0 END) + SUM(CASE WHEN over_under_outcome = 'win' THEN 1 ELSE 0 END), 0) as wins,
+				COALESCE(SUM(CASE WHEN spread_outcome = 'loss' THEN 1 ELSE 0 END) + SUM(CASE WHEN over_under_outcome = 'loss' THEN 1 ELSE 0 END), 0) as losses,
+				COALESCE(SUM(CASE WHEN spread_outcome = 'push' THEN 1 ELSE 0 END) + SUM(CASE WHEN over_under_outcome = 'push' THEN 1 ELSE 0 END), 0) as pushes
+			`).
+			Scan(&counts)
+
+		stats.CorrectPicks = counts.Wins
+		stats.IncorrectPicks = counts.Losses
+		stats.PushPicks = counts.Pushes
+
+		// Pushes are neither wins nor losses
+		if decided := stats.CorrectPicks + stats.IncorrectPicks; decided > 0 {
+			stats.WinPercentage = float64(stats.CorrectPicks) / float64(decided) * 100
 		}
 
 		// Scan maps to any struct, not just GORM model
@@ -287,3 +514,208 @@ func GetPickStats(a *app.App) http.HandlerFunc {
 		json.NewEncoder(w).Encode(stats)
 	}
 }
+
+// PickHistoryEntry is a pick change with the address it came from, which only the commissioner sees
+type PickHistoryEntry struct {
+	models.PickChange
+	IP string `json:"ip"`
+}
+
+// GetWeekPickHistory returns a handler for a league commissioner to view every change made to picks in a week
+// Filter to one member with ?user_id=
+func GetWeekPickHistory(a *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetUserFromContext(r)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		week, ok := verifyWeekPermission(a, w, claims, chi.URLParam(r, "id"))
+		if !ok {
+			return
+		}
+
+		query := a.DB.Model(&models.PickChange{}).
+			Joins("JOIN games ON games.id = pick_changes.game_id").
+			Where("pick_changes.league_id = ? AND games.week_id = ?", week.Season.LeagueID, week.ID)
+		if userID := r.URL.Query().Get("user_id"); userID != "" {
+			query = query.Where("pick_changes.user_id = ?", userID)
+		}
+
+		var changes []models.PickChange
+		if err := query.Preload("Actor").
+			Order("pick_changes.created_at ASC, pick_changes.id ASC").
+			Find(&changes).Error; err != nil {
+			http.Error(w, "Error fetching pick history", http.StatusInternalServerError)
+			return
+		}
+
+		history := make([]PickHistoryEntry, len(changes))
+		for i, change := range changes {
+			history[i] = PickHistoryEntry{PickChange: change, IP: change.IP}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(history)
+	}
+}
+
+type OverridePickRequest struct {
+	UserID          uint   `json:"user_id"` // Member the pick is for
+	GameID          uint   `json:"game_id"`
+	PickedTeamID    uint   `json:"picked_team_id"`
+	PickedOverUnder string `json:"picked_over_under"` // "over" or "under"
+	Confidence      int    `json:"confidence"`
+	Reason          string `json:"reason"` // Optional note kept in the pick's history, e.g. "Texted before kickoff"
+}
+
+// OverridePick returns a handler for a league owner to enter or change a member's pick (owner only)
+// The pick isn't held to the deadline, is flagged as commissioner-entered, and shows in the league's override log
+func OverridePick(a *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetUserFromContext(r)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		leagueID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+		if err != nil {
+			http.Error(w, "Invalid league ID", http.StatusBadRequest)
+			return
+		}
+
+		var league models.League
+		if err := a.DB.First(&league, leagueID).Error; err != nil {
+			validation.RespondWithError(w, http.StatusNotFound, "League not found", "LEAGUE_NOT_FOUND", nil)
+			return
+		}
+
+		// Verify user has permission to manage this league
+		if !canManageLeague(claims, league.OwnerID) {
+			validation.RespondWithError(w, http.StatusForbidden, "You don't have permission to manage this league", "FORBIDDEN", nil)
+			return
+		}
+
+		var req OverridePickRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			validation.RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_JSON", nil)
+			return
+		}
+
+		var membership models.LeagueMembership
+		if err := a.DB.Where("league_id = ? AND user_id = ?", league.ID, req.UserID).First(&membership).Error; err != nil {
+			validation.RespondWithError(w, http.StatusNotFound, "User is not a member of this league", "NOT_MEMBER", nil)
+			return
+		}
+
+		var game models.Game
+		if err := a.DB.Preload("Week.Season").First(&game, req.GameID).Error; err != nil || game.Week.Season.LeagueID != league.ID {
+			validation.RespondWithError(w, http.StatusNotFound, "Game not found in this league", "GAME_NOT_FOUND", nil)
+			return
+		}
+
+		entry := picks.Entry{
+			GameID:          req.GameID,
+			PickedTeamID:    req.PickedTeamID,
+			PickedOverUnder: req.PickedOverUnder,
+			Confidence:      req.Confidence,
+		}
+		if problem := picks.CheckOverride(&game, entry); problem != nil {
+			status := http.StatusBadRequest
+			if problem.Code == picks.CodeGameNotPlayed {
+				status = http.StatusConflict
+			}
+			validation.RespondWithError(w, status, problem.Message, problem.Code, nil)
+			return
+		}
+
+		settings, err := scoring.LoadSettings(a.DB, league.ID)
+		if err != nil {
+			http.Error(w, "Error loading league settings", http.StatusInternalServerError)
+			return
+		}
+		if settings.Format == models.LeagueFormatConfidence {
+			problems, err := picks.CheckConfidence(a.DB, league.ID, req.UserID, game.WeekID, []picks.Entry{entry})
+			if err != nil {
+				http.Error(w, "Error checking confidence values", http.StatusInternalServerError)
+				return
+			}
+			if len(problems) > 0 {
+				validation.RespondWithError(w, http.StatusBadRequest, "Validation failed", "VALIDATION_ERROR", map[string]string{
+					"confidence": problems[0].Message,
+				})
+				return
+			}
+		}
+
+		audit := pickAudit(r, claims.UserID)
+		audit.Actor.Reason = req.Reason
+
+		var pick *models.Pick
+		var created bool
+		err = a.DB.Transaction(func(tx *gorm.DB) error {
+			var err error
+			if pick, created, err = picks.Override(tx, league.ID, req.UserID, &game, entry, audit); err != nil {
+				return err
+			}
+			return leaderboard.RefreshMembers(tx, league.ID, game.WeekID, req.UserID)
+		})
+		if err != nil {
+			validation.RespondWithError(w, http.StatusInternalServerError, "Error saving pick", "DATABASE_ERROR", nil)
+			return
+		}
+
+		// Load relationships
+		a.DB.Preload("Game").Preload("PickedTeam").First(pick, pick.ID)
+
+		w.Header().Set("Content-Type", "application/json")
+		if created {
+			w.WriteHeader(http.StatusCreated)
+		}
+		json.NewEncoder(w).Encode(pick)
+	}
+}
+
+// GetPickOverrides returns a handler for the picks a league's commissioner entered or changed for members
+// Any member can view it. Filter to one week with ?week_id=
+func GetPickOverrides(a *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetUserFromContext(r)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		leagueID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+		if err != nil {
+			http.Error(w, "Invalid league ID", http.StatusBadRequest)
+			return
+		}
+
+		// Verify user is a member of this league
+		var membership models.LeagueMembership
+		if err := a.DB.Where("league_id = ? AND user_id = ?", leagueID, claims.UserID).First(&membership).Error; err != nil && !claims.IsGlobalAdmin {
+			http.Error(w, "You are not a member of this league", http.StatusForbidden)
+			return
+		}
+
+		query := a.DB.Model(&models.PickChange{}).Where("pick_changes.league_id = ? AND pick_changes.override = ?", leagueID, true)
+		if weekID := r.URL.Query().Get("week_id"); weekID != "" {
+			query = query.Joins("JOIN games ON games.id = pick_changes.game_id").
+				Where("games.week_id = ?", weekID)
+		}
+
+		var changes []models.PickChange
+		if err := query.Preload("User").Preload("Actor").
+			Order("pick_changes.created_at ASC, pick_changes.id ASC").
+			Find(&changes).Error; err != nil {
+			http.Error(w, "Error fetching pick overrides", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(changes)
+	}
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ckinger23/mountaintop/internal/app"
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPickChangeIP_OnlyForCommissioner(t *testing.T) {
+	db := setupTestDB(t)
+	owner := models.User{Username: "owner", Email: "owner@example.com", PasswordHash: "hash"}
+	member := models.User{Username: "member", Email: "member@example.com", PasswordHash: "hash"}
+	db.Create(&owner)
+	db.Create(&member)
+	league := models.League{Name: "League", Code: "LEAGUE-1", OwnerID: owner.ID, IsActive: true}
+	db.Create(&league)
+	db.Create(&models.LeagueMembership{LeagueID: league.ID, UserID: owner.ID, Role: "owner", JoinedAt: time.Now()})
+	db.Create(&models.LeagueMembership{LeagueID: league.ID, UserID: member.ID, Role: "member", JoinedAt: time.Now()})
+	season := models.Season{LeagueID: league.ID, Year: 2025}
+	db.Create(&season)
+	week := models.Week{SeasonID: season.ID, WeekNumber: 1, Name: "Week 1", Status: "picking"}
+	db.Create(&week)
+	teams := []models.Team{{Name: "A", Abbreviation: "A"}, {Name: "B", Abbreviation: "B"}}
+	db.Create(&teams)
+	game := models.Game{WeekID: week.ID, HomeTeamID: teams[0].ID, AwayTeamID: teams[1].ID, GameTime: time.Now()}
+	db.Create(&game)
+	db.Create(&models.PickChange{PickID: 1, LeagueID: league.ID, UserID: member.ID, GameID: game.ID, Action: "create",
+		NewPickedTeamID: teams[0].ID, NewPickedOverUnder: "over", Source: "user", ActorID: &owner.ID, IP: "203.0.113.9", Override: true})
+
+	a := app.NewApp(db)
+
+	// Members see the override log without the commissioner's address
+	rec := serveAs(withID(GetPickOverrides(a), league.ID), member.ID, http.MethodGet, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var overrides []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &overrides))
+	if assert.Len(t, overrides, 1) {
+		assert.NotContains(t, overrides[0], "ip")
+	}
+
+	// The commissioner's week history includes it
+	rec = serveAs(withID(GetWeekPickHistory(a), week.ID), owner.ID, http.MethodGet, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var history []PickHistoryEntry
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &history))
+	if assert.Len(t, history, 1) {
+		assert.Equal(t, "203.0.113.9", history[0].IP)
+	}
+}
@@ -0,0 +1,439 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/ckinger23/mountaintop/internal/app"
+	"github.com/ckinger23/mountaintop/internal/middleware"
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/results"
+	"github.com/ckinger23/mountaintop/internal/schedule"
+	"github.com/ckinger23/mountaintop/internal/teammatch"
+	"github.com/ckinger23/mountaintop/internal/validation"
+	"github.com/ckinger23/mountaintop/internal/weeks"
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+// ImportScheduleResponse lists the games an import created, or would create in a dry run
+type ImportScheduleResponse struct {
+	DryRun bool          `json:"dry_run"`
+	Games  []models.Game `json:"games"`
+}
+
+// ScheduleErrorResponse reports the rows that stopped an import
+type ScheduleErrorResponse struct {
+	validation.ErrorResponse
+	Rows []schedule.RowError `json:"rows"`
+}
+
+// ImportSeasonSchedule imports games for any weeks of a season; each row names its week number
+func ImportSeasonSchedule(a *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetUserFromContext(r)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var season models.Season
+		if err := a.DB.Preload("League").First(&season, chi.URLParam(r, "id")).Error; err != nil {
+			validation.RespondWithError(w, http.StatusNotFound, "Season not found", "SEASON_NOT_FOUND", nil)
+			return
+		}
+
+		// Verify user has permission to manage this league
+		if !canManageLeague(claims, season.League.OwnerID) {
+			validation.RespondWithError(w, http.StatusForbidden, "You don't have permission to manage this league", "FORBIDDEN", nil)
+			return
+		}
+
+		importSchedule(a, w, r, claims, season.ID, nil)
+	}
+}
+
+// ImportWeekSchedule imports games into a single week
+func ImportWeekSchedule(a *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetUserFromContext(r)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		week, ok := verifyWeekPermission(a, w, claims, chi.URLParam(r, "id"))
+		if !ok {
+			return // error already sent by verifyWeekPermission
+		}
+
+		importSchedule(a, w, r, claims, week.SeasonID, week)
+	}
+}
+
+// importSchedule parses the request body as a CSV (Content-Type: text/csv) or JSON schedule and creates
+// every game in one transaction. Nothing is created if any row is invalid, or when ?dry_run=true
+func importSchedule(a *app.App, w http.ResponseWriter, r *http.Request, claims *middleware.Claims, seasonID uint, into *models.Week) {
+	format := schedule.FormatJSON
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "text/csv") {
+		format = schedule.FormatCSV
+	}
+
+	rows, err := schedule.Parse(format, r.Body)
+	if err != nil {
+		validation.RespondWithError(w, http.StatusBadRequest, "Invalid schedule", "INVALID_SCHEDULE", map[string]string{
+			"schedule": err.Error(),
+		})
+		return
+	}
+	if len(rows) == 0 {
+		validation.RespondWithError(w, http.StatusBadRequest, "Schedule has no games", "INVALID_SCHEDULE", nil)
+		return
+	}
+
+	resolver, err := teammatch.NewResolver(a.DB)
+	if err != nil {
+		http.Error(w, "Error fetching teams", http.StatusInternalServerError)
+		return
+	}
+
+	games, rowErrors, err := schedule.Plan(a.DB, seasonID, into, rows, resolver)
+	if err != nil {
+		http.Error(w, "Error checking schedule", http.StatusInternalServerError)
+		return
+	}
+	if len(rowErrors) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(ScheduleErrorResponse{
+			ErrorResponse: validation.ErrorResponse{Error: "Schedule has invalid rows", Code: "VALIDATION_ERROR"},
+			Rows:          rowErrors,
+		})
+		return
+	}
+
+	response := ImportScheduleResponse{DryRun: r.URL.Query().Get("dry_run") == "true", Games: games}
+	if response.DryRun {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	err = a.DB.Transaction(func(tx *gorm.DB) error {
+		return schedule.Insert(tx, games, weeks.UserActor(claims.UserID))
+	})
+	if err != nil {
+		validation.RespondWithError(w, http.StatusInternalServerError, "Error creating games", "DATABASE_ERROR", nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+type CreateScheduledGameRequest struct {
+	Year       int     `json:"year"`
+	WeekNumber int     `json:"week_number"`
+	HomeTeamID uint    `json:"home_team_id"`
+	AwayTeamID uint    `json:"away_team_id"`
+	GameTime   string  `json:"game_time"` // ISO 8601 format
+	HomeSpread float64 `json:"home_spread"`
+	Total      float64 `json:"total"`
+	ExternalID string  `json:"external_id"`
+}
+
+// GetScheduledGames returns a handler for browsing the shared master schedule
+// Supports ?year= and ?week= filters
+func GetScheduledGames(a *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := a.DB.Preload("HomeTeam").Preload("AwayTeam")
+		if year := r.URL.Query().Get("year"); year != "" {
+			query = query.Where("year = ?", year)
+		}
+		if week := r.URL.Query().Get("week"); week != "" {
+			query = query.Where("week_number = ?", week)
+		}
+
+		var games []models.ScheduledGame
+		if err := query.Order("year, week_number, game_time").Find(&games).Error; err != nil {
+			http.Error(w, "Error fetching schedule", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(games)
+	}
+}
+
+// CreateScheduledGame returns a handler for adding a game to the master schedule (global admin only)
+func CreateScheduledGame(a *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetUserFromContext(r)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if !claims.IsGlobalAdmin {
+			validation.RespondWithError(w, http.StatusForbidden, "Only global admins can manage the master schedule", "FORBIDDEN", nil)
+			return
+		}
+
+		var req CreateScheduledGameRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			validation.RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_JSON", nil)
+			return
+		}
+
+		gameTime, valErr := validation.ValidateGameTime(req.GameTime)
+		if valErr != nil {
+			validation.RespondWithValidationError(w, valErr)
+			return
+		}
+		if valErr := validation.ValidateScheduledGame(req.Year, req.WeekNumber, req.HomeTeamID, req.AwayTeamID, gameTime, req.HomeSpread, req.Total); valErr != nil {
+			validation.RespondWithValidationError(w, valErr)
+			return
+		}
+
+		var teamCount int64
+		a.DB.Model(&models.Team{}).Where("id IN ?", []uint{req.HomeTeamID, req.AwayTeamID}).Count(&teamCount)
+		if teamCount != 2 {
+			validation.RespondWithError(w, http.StatusBadRequest, "Team not found", "TEAM_NOT_FOUND", map[string]string{
+				"teams": "Both teams must exist",
+			})
+			return
+		}
+
+		game := models.ScheduledGame{
+			Year:       req.Year,
+			WeekNumber: req.WeekNumber,
+			HomeTeamID: req.HomeTeamID,
+			AwayTeamID: req.AwayTeamID,
+			GameTime:   gameTime,
+			HomeSpread: req.HomeSpread,
+			Total:      req.Total,
+			ExternalID: req.ExternalID,
+		}
+		if err := a.DB.Create(&game).Error; err != nil {
+			validation.RespondWithError(w, http.StatusInternalServerError, "Error creating game", "DATABASE_ERROR", nil)
+			return
+		}
+
+		a.DB.Preload("HomeTeam").Preload("AwayTeam").First(&game, game.ID)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(game)
+	}
+}
+
+// ScheduledGameResultResponse is the updated master schedule game and what it changed in each league
+type ScheduledGameResultResponse struct {
+	models.ScheduledGame
+	Leagues []results.LeagueOutcome `json:"leagues"`
+}
+
+// UpdateScheduledGameResult returns a handler for entering a master schedule result (global admin only)
+// The result is recorded on every league's copy of the game, scoring all of their picks at once
+func UpdateScheduledGameResult(a *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetUserFromContext(r)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if !claims.IsGlobalAdmin {
+			validation.RespondWithError(w, http.StatusForbidden, "Only global admins can manage the master schedule", "FORBIDDEN", nil)
+			return
+		}
+
+		var req UpdateGameResultRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			validation.RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_JSON", nil)
+			return
+		}
+
+		if valErr := validation.ValidateUpdateGameResult(&req.HomeScore, &req.AwayScore, req.IsFinal); valErr != nil {
+			validation.RespondWithValidationError(w, valErr)
+			return
+		}
+
+		var game models.ScheduledGame
+		if err := a.DB.First(&game, chi.URLParam(r, "id")).Error; err != nil {
+			validation.RespondWithError(w, http.StatusNotFound, "Game not found", "GAME_NOT_FOUND", nil)
+			return
+		}
+
+		actor := weeks.UserActor(claims.UserID)
+		actor.Reason = req.Reason
+		submission := results.Submission{
+			HomeScore: req.HomeScore,
+			AwayScore: req.AwayScore,
+			IsFinal:   req.IsFinal,
+			Override:  req.Override,
+		}
+
+		// Every league is scored in one transaction so no league sees the result before the others
+		var outcomes []results.LeagueOutcome
+		err := a.DB.Transaction(func(tx *gorm.DB) error {
+			var err error
+			outcomes, err = results.RecordScheduled(tx, &game, submission, actor)
+			return err
+		})
+		if errors.Is(err, results.ErrGameNotPlayed) {
+			validation.RespondWithError(w, http.StatusConflict, "Game is postponed or cancelled", "GAME_NOT_PLAYED", nil)
+			return
+		}
+		if errors.Is(err, results.ErrWeekFinished) {
+			validation.RespondWithError(w, http.StatusConflict, "A league's week with this game is finished", "WEEK_FINISHED", map[string]string{
+				"override": "Set override to correct a result after the week is finished",
+			})
+			return
+		}
+		if err != nil {
+			validation.RespondWithError(w, http.StatusInternalServerError, "Error calculating pick results", "SCORING_ERROR", nil)
+			return
+		}
+
+		a.DB.Preload("HomeTeam").Preload("AwayTeam").First(&game, game.ID)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ScheduledGameResultResponse{ScheduledGame: game, Leagues: outcomes})
+	}
+}
+
+type UpdateScheduledGameStatusRequest struct {
+	Status string `json:"status"` // scheduled, in_progress, postponed or cancelled
+}
+
+// ScheduledGameStatusResponse is the updated master schedule game and the status of each league's copy
+type ScheduledGameStatusResponse struct {
+	models.ScheduledGame
+	Leagues []results.LeagueStatus `json:"leagues"`
+}
+
+// UpdateScheduledGameStatus returns a handler for postponing, cancelling or restarting a master schedule game (global admin only)
+// Every league's copy of the game moves with it; cancelling voids their picks
+func UpdateScheduledGameStatus(a *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetUserFromContext(r)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if !claims.IsGlobalAdmin {
+			validation.RespondWithError(w, http.StatusForbidden, "Only global admins can manage the master schedule", "FORBIDDEN", nil)
+			return
+		}
+
+		var req UpdateScheduledGameStatusRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			validation.RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_JSON", nil)
+			return
+		}
+
+		var game models.ScheduledGame
+		if err := a.DB.First(&game, chi.URLParam(r, "id")).Error; err != nil {
+			validation.RespondWithError(w, http.StatusNotFound, "Game not found", "GAME_NOT_FOUND", nil)
+			return
+		}
+
+		var statuses []results.LeagueStatus
+		err := a.DB.Transaction(func(tx *gorm.DB) error {
+			var err error
+			statuses, err = results.SetScheduledStatus(tx, &game, req.Status)
+			return err
+		})
+		var valErr *validation.ValidationError
+		if errors.As(err, &valErr) {
+			validation.RespondWithValidationError(w, valErr)
+			return
+		}
+		if err != nil {
+			validation.RespondWithError(w, http.StatusInternalServerError, "Error updating game status", "DATABASE_ERROR", nil)
+			return
+		}
+
+		a.DB.Preload("HomeTeam").Preload("AwayTeam").First(&game, game.ID)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ScheduledGameStatusResponse{ScheduledGame: game, Leagues: statuses})
+	}
+}
+
+type SelectGamesRequest struct {
+	ScheduledGameIDs []uint `json:"scheduled_game_ids"`
+}
+
+// SelectWeekGames returns a handler for adding master schedule games to a league week's slate
+func SelectWeekGames(a *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetUserFromContext(r)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		week, ok := verifyWeekPermission(a, w, claims, chi.URLParam(r, "id"))
+		if !ok {
+			return // error already sent by verifyWeekPermission
+		}
+
+		if week.Status != "creating" && week.Status != "picking" {
+			validation.RespondWithError(w, http.StatusBadRequest, "Cannot change this week's games", "INVALID_STATUS", map[string]string{
+				"status": "Games can only be added while the week is in 'creating' or 'picking' status",
+			})
+			return
+		}
+
+		var req SelectGamesRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			validation.RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_JSON", nil)
+			return
+		}
+		if len(req.ScheduledGameIDs) == 0 {
+			validation.RespondWithError(w, http.StatusBadRequest, "Validation failed", "VALIDATION_ERROR", map[string]string{
+				"scheduled_game_ids": "At least one game is required",
+			})
+			return
+		}
+
+		var scheduled []models.ScheduledGame
+		if err := a.DB.Where("id IN ?", req.ScheduledGameIDs).Order("game_time").Find(&scheduled).Error; err != nil {
+			http.Error(w, "Error fetching schedule", http.StatusInternalServerError)
+			return
+		}
+		if len(scheduled) != len(req.ScheduledGameIDs) {
+			validation.RespondWithError(w, http.StatusBadRequest, "Game not found", "GAME_NOT_FOUND", map[string]string{
+				"scheduled_game_ids": "Every game must be on the master schedule, listed once",
+			})
+			return
+		}
+
+		var games []models.Game
+		err := a.DB.Transaction(func(tx *gorm.DB) error {
+			var err error
+			games, err = schedule.Select(tx, week, scheduled, weeks.UserActor(claims.UserID))
+			return err
+		})
+		var valErr *validation.ValidationError
+		if errors.As(err, &valErr) {
+			validation.RespondWithValidationError(w, valErr)
+			return
+		}
+		if err != nil {
+			validation.RespondWithError(w, http.StatusInternalServerError, "Error adding games", "DATABASE_ERROR", nil)
+			return
+		}
+
+		for i := range games {
+			a.DB.Preload("HomeTeam").Preload("AwayTeam").First(&games[i], games[i].ID)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(games)
+	}
+}
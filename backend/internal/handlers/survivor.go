@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ckinger23/mountaintop/internal/app"
+	"github.com/ckinger23/mountaintop/internal/middleware"
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/scoring"
+	"github.com/ckinger23/mountaintop/internal/survivor"
+	"github.com/ckinger23/mountaintop/internal/validation"
+	"github.com/ckinger23/mountaintop/internal/weeks"
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+)
+
+type SubmitSurvivorPickRequest struct {
+	LeagueID uint `json:"league_id"`
+	WeekID   uint `json:"week_id"`
+	TeamID   uint `json:"team_id"`
+}
+
+// SubmitSurvivorPick returns a handler for creating or changing a member's survivor pick for a week
+func SubmitSurvivorPick(a *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetUserFromContext(r)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var req SubmitSurvivorPickRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			validation.RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_JSON", nil)
+			return
+		}
+
+		// Check that the week exists and belongs to this league
+		var week models.Week
+		if err := a.DB.Preload("Season").First(&week, req.WeekID).Error; err != nil || week.Season.LeagueID != req.LeagueID {
+			validation.RespondWithError(w, http.StatusNotFound, "Week not found", "WEEK_NOT_FOUND", nil)
+			return
+		}
+
+		settings, err := scoring.LoadSettings(a.DB, req.LeagueID)
+		if err != nil {
+			validation.RespondWithError(w, http.StatusInternalServerError, "Error loading league settings", "DATABASE_ERROR", nil)
+			return
+		}
+		if settings.Format != models.LeagueFormatSurvivor {
+			validation.RespondWithError(w, http.StatusBadRequest, "This league is not a survivor league", "NOT_SURVIVOR_LEAGUE", nil)
+			return
+		}
+
+		// Verify user is a member of this league
+		var membership models.LeagueMembership
+		if err := a.DB.Where("league_id = ? AND user_id = ?", req.LeagueID, claims.UserID).First(&membership).Error; err != nil {
+			validation.RespondWithError(w, http.StatusForbidden, "You are not a member of this league", "FORBIDDEN", nil)
+			return
+		}
+
+		// Check week status
+		if week.Status != "picking" {
+			validation.RespondWithError(w, http.StatusForbidden, "Picks are not open for this week", "WEEK_NOT_OPEN", nil)
+			return
+		}
+
+		// The team must play in a game this week
+		var game models.Game
+		if err := a.DB.Where("week_id = ? AND (home_team_id = ? OR away_team_id = ?)", req.WeekID, req.TeamID, req.TeamID).First(&game).Error; err != nil {
+			validation.RespondWithError(w, http.StatusBadRequest, "Team does not play this week", "INVALID_TEAM", map[string]string{
+				"team_id": "The selected team has no game in this week",
+			})
+			return
+		}
+		if game.IsFinal {
+			validation.RespondWithError(w, http.StatusForbidden, "Cannot pick a game that is final", "GAME_IS_FINAL", nil)
+			return
+		}
+
+		// Check the pick deadline, or the game's kickoff for leagues that lock at kickoff
+		now := time.Now()
+		if weeks.GameLocked(&week, &game, settings.LockAtKickoff, now) {
+			respondPickLocked(w, settings.LockAtKickoff)
+			return
+		}
+
+		// Eliminated members are out for the rest of the season
+		eliminated, err := survivor.IsEliminated(a.DB, req.LeagueID, week.SeasonID, claims.UserID)
+		if err != nil {
+			validation.RespondWithError(w, http.StatusInternalServerError, "Error checking survivor status", "DATABASE_ERROR", nil)
+			return
+		}
+		if eliminated {
+			validation.RespondWithError(w, http.StatusForbidden, "You have been eliminated from this survivor pool", "ELIMINATED", nil)
+			return
+		}
+
+		// Teams can only be used once per season; a voided pick (e.g. its game was postponed) frees the team up again
+		var reused int64
+		a.DB.Model(&models.SurvivorPick{}).
+			Where("league_id = ? AND user_id = ? AND season_id = ? AND team_id = ? AND week_id <> ? AND outcome <> ?",
+				req.LeagueID, claims.UserID, week.SeasonID, req.TeamID, req.WeekID, models.PickOutcomeVoid).
+			Count(&reused)
+		if reused > 0 {
+			validation.RespondWithError(w, http.StatusBadRequest, "Team already used this season", "TEAM_ALREADY_USED", map[string]string{
+				"team_id": "You have already picked this team in an earlier week",
+			})
+			return
+		}
+
+		// Create or replace this week's pick
+		var pick models.SurvivorPick
+		err = a.DB.Where("league_id = ? AND user_id = ? AND week_id = ?", req.LeagueID, claims.UserID, req.WeekID).First(&pick).Error
+		status := http.StatusOK
+		if err == nil && settings.LockAtKickoff && pick.GameID != game.ID {
+			// A pick on a game that has kicked off can't be swapped out
+			var pickedGame models.Game
+			if err := a.DB.First(&pickedGame, pick.GameID).Error; err == nil && weeks.GameLocked(&week, &pickedGame, true, now) {
+				respondPickLocked(w, true)
+				return
+			}
+		}
+		if err != nil {
+			status = http.StatusCreated
+			pick = models.SurvivorPick{
+				LeagueID: req.LeagueID,
+				UserID:   claims.UserID,
+				SeasonID: week.SeasonID,
+				WeekID:   req.WeekID,
+			}
+		}
+		pick.GameID = game.ID
+		pick.TeamID = req.TeamID
+		// A pick made again after being voided counts again
+		pick.Outcome = models.PickOutcomePending
+
+		err = a.DB.Transaction(func(tx *gorm.DB) error {
+			// A voided pick on the team in another week would still hold the team's once-per-season slot
+			if err := tx.Unscoped().
+				Where("league_id = ? AND user_id = ? AND season_id = ? AND team_id = ? AND week_id <> ? AND outcome = ?",
+					req.LeagueID, claims.UserID, week.SeasonID, req.TeamID, req.WeekID, models.PickOutcomeVoid).
+				Delete(&models.SurvivorPick{}).Error; err != nil {
+				return err
+			}
+			return tx.Save(&pick).Error
+		})
+		if err != nil {
+			validation.RespondWithError(w, http.StatusInternalServerError, "Error saving survivor pick", "DATABASE_ERROR", nil)
+			return
+		}
+
+		// Load relationships
+		a.DB.Preload("Game.HomeTeam").Preload("Game.AwayTeam").Preload("Team").First(&pick, pick.ID)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(pick)
+	}
+}
+
+// respondPickLocked sends the error for a survivor pick that can no longer be changed
+func respondPickLocked(w http.ResponseWriter, lockAtKickoff bool) {
+	if lockAtKickoff {
+		validation.RespondWithError(w, http.StatusForbidden, "This game has already kicked off", "GAME_STARTED", nil)
+		return
+	}
+	validation.RespondWithError(w, http.StatusForbidden, "Pick deadline has passed for this week", "DEADLINE_PASSED", nil)
+}
+
+// GetSurvivorStandings returns a handler listing alive and eliminated entrants for a survivor league
+// Defaults to the league's active season unless season_id is given
+func GetSurvivorStandings(a *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetUserFromContext(r)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		leagueID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+		if err != nil {
+			http.Error(w, "Invalid league ID", http.StatusBadRequest)
+			return
+		}
+
+		// Verify user is a member of this league
+		var membership models.LeagueMembership
+		if err := a.DB.Where("league_id = ? AND user_id = ?", leagueID, claims.UserID).First(&membership).Error; err != nil && !claims.IsGlobalAdmin {
+			http.Error(w, "You are not a member of this league", http.StatusForbidden)
+			return
+		}
+
+		var season models.Season
+		query := a.DB.Where("league_id = ?", leagueID)
+		if seasonID := r.URL.Query().Get("season_id"); seasonID != "" {
+			query = query.Where("id = ?", seasonID)
+		} else {
+			query = query.Where("is_active = ?", true)
+		}
+		if err := query.First(&season).Error; err != nil {
+			http.Error(w, "Season not found", http.StatusNotFound)
+			return
+		}
+
+		entrants, err := survivor.Standings(a.DB, uint(leagueID), season.ID)
+		if err != nil {
+			http.Error(w, "Error fetching survivor standings", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(entrants)
+	}
+}
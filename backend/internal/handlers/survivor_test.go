@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ckinger23/mountaintop/internal/app"
+	"github.com/ckinger23/mountaintop/internal/middleware"
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/results"
+	"github.com/ckinger23/mountaintop/internal/survivor"
+	"github.com/ckinger23/mountaintop/internal/weeks"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupTestDB creates an in-memory SQLite database for testing
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+
+	err = db.AutoMigrate(
+		&models.League{},
+		&models.LeagueMembership{},
+		&models.LeagueSettings{},
+		&models.User{},
+		&models.Season{},
+		&models.Week{},
+		&models.WeekTransition{},
+		&models.Team{},
+		&models.ScheduledGame{},
+		&models.Game{},
+		&models.GameResult{},
+		&models.Pick{},
+		&models.PickChange{},
+		&models.Standing{},
+		&models.SurvivorPick{},
+		&models.BracketSlot{},
+		&models.BracketPick{},
+	)
+	if err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	return db
+}
+
+// serveAs runs a handler for a request made by userID with a JSON body
+func serveAs(handler http.HandlerFunc, userID uint, method string, body interface{}) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, "/", bytes.NewReader(payload))
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, &middleware.Claims{UserID: userID}))
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+// survivorFixture holds the records created by seedPostponedSurvivorPick
+type survivorFixture struct {
+	league models.League
+	season models.Season
+	member models.User
+	week1  models.Week
+	week2  models.Week
+	teams  []models.Team
+	other  models.Game // Still in week 1, between teams[2] and teams[3]
+}
+
+// seedPostponedSurvivorPick has a survivor member pick teams[0] in week 1, then moves that game to week 2
+func seedPostponedSurvivorPick(t *testing.T, db *gorm.DB) survivorFixture {
+	var f survivorFixture
+	f.member = models.User{Username: "member", Email: "member@example.com", PasswordHash: "hash"}
+	db.Create(&f.member)
+	f.league = models.League{Name: "Survivor", Code: "SURV-1", OwnerID: f.member.ID, IsActive: true}
+	db.Create(&f.league)
+	db.Create(&models.LeagueMembership{LeagueID: f.league.ID, UserID: f.member.ID, Role: "owner", JoinedAt: time.Now()})
+	db.Create(&models.LeagueSettings{LeagueID: f.league.ID, Format: models.LeagueFormatSurvivor})
+	f.season = models.Season{LeagueID: f.league.ID, Year: 2025}
+	db.Create(&f.season)
+	f.week1 = models.Week{SeasonID: f.season.ID, WeekNumber: 1, Name: "Week 1", Status: "picking"}
+	f.week2 = models.Week{SeasonID: f.season.ID, WeekNumber: 2, Name: "Week 2", Status: "picking"}
+	db.Create(&f.week1)
+	db.Create(&f.week2)
+
+	f.teams = []models.Team{{Name: "A", Abbreviation: "A"}, {Name: "B", Abbreviation: "B"}, {Name: "C", Abbreviation: "C"}, {Name: "D", Abbreviation: "D"}}
+	db.Create(&f.teams)
+	kickoff := time.Now().Add(24 * time.Hour)
+	postponed := models.Game{WeekID: f.week1.ID, HomeTeamID: f.teams[0].ID, AwayTeamID: f.teams[1].ID, GameTime: kickoff}
+	f.other = models.Game{WeekID: f.week1.ID, HomeTeamID: f.teams[2].ID, AwayTeamID: f.teams[3].ID, GameTime: kickoff}
+	db.Create(&postponed)
+	db.Create(&f.other)
+
+	rec := serveAs(SubmitSurvivorPick(app.NewApp(db)), f.member.ID, http.MethodPost, SubmitSurvivorPickRequest{LeagueID: f.league.ID, WeekID: f.week1.ID, TeamID: f.teams[0].ID})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Failed to submit survivor pick: %d %s", rec.Code, rec.Body.String())
+	}
+
+	// The picked game moves to week 2, voiding the week 1 pick
+	db.Preload("Week.Season").First(&postponed, postponed.ID)
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		return results.Postpone(tx, &postponed, &f.week2, kickoff.AddDate(0, 0, 7))
+	}); err != nil {
+		t.Fatalf("Failed to postpone game: %v", err)
+	}
+	return f
+}
+
+func TestSubmitSurvivorPick_AfterPostponement(t *testing.T) {
+	db := setupTestDB(t)
+	f := seedPostponedSurvivorPick(t, db)
+
+	// Picking week 1 again counts, so losing it eliminates the member
+	rec := serveAs(SubmitSurvivorPick(app.NewApp(db)), f.member.ID, http.MethodPost, SubmitSurvivorPickRequest{LeagueID: f.league.ID, WeekID: f.week1.ID, TeamID: f.teams[2].ID})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var pick models.SurvivorPick
+	db.Where("week_id = ? AND user_id = ?", f.week1.ID, f.member.ID).First(&pick)
+	assert.Equal(t, models.PickOutcomePending, pick.Outcome)
+
+	db.Preload("Week").First(&f.other, f.other.ID)
+	assert.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+		_, err := results.Record(tx, &f.other, results.Submission{HomeScore: 10, AwayScore: 20, IsFinal: true}, weeks.UserActor(f.member.ID))
+		return err
+	}))
+
+	db.First(&pick, pick.ID)
+	assert.Equal(t, models.PickOutcomeLoss, pick.Outcome)
+	eliminated, err := survivor.IsEliminated(db, f.league.ID, f.season.ID, f.member.ID)
+	assert.NoError(t, err)
+	assert.True(t, eliminated)
+}
+
+func TestSubmitSurvivorPick_VoidedTeamReusable(t *testing.T) {
+	db := setupTestDB(t)
+	f := seedPostponedSurvivorPick(t, db)
+
+	// The voided week 1 pick doesn't use up its team
+	rec := serveAs(SubmitSurvivorPick(app.NewApp(db)), f.member.ID, http.MethodPost, SubmitSurvivorPickRequest{LeagueID: f.league.ID, WeekID: f.week2.ID, TeamID: f.teams[0].ID})
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var picks []models.SurvivorPick
+	db.Where("user_id = ?", f.member.ID).Find(&picks)
+	if assert.Len(t, picks, 1) {
+		assert.Equal(t, f.week2.ID, picks[0].WeekID)
+		assert.Equal(t, models.PickOutcomePending, picks[0].Outcome)
+	}
+}
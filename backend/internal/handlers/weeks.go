@@ -2,19 +2,24 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/ckinger23/mountaintop/internal/app"
 	"github.com/ckinger23/mountaintop/internal/middleware"
 	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/picks"
 	"github.com/ckinger23/mountaintop/internal/validation"
+	"github.com/ckinger23/mountaintop/internal/weeks"
 	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
 )
 
 type WeekRequest struct {
-	SeasonID   uint   `json:"season_id"`
-	WeekNumber int    `json:"week_number"`
-	Name       string `json:"name"`
+	SeasonID     uint   `json:"season_id"`
+	WeekNumber   int    `json:"week_number"`
+	Name         string `json:"name"`
+	IsPostseason bool   `json:"is_postseason"`
 }
 
 // verifyWeekPermission checks if the user can manage the league that owns the given week
@@ -34,6 +39,19 @@ func verifyWeekPermission(a *app.App, w http.ResponseWriter, claims *middleware.
 	return &week, true
 }
 
+// respondTransitionError sends the response for a failed weeks.Transition
+func respondTransitionError(w http.ResponseWriter, err error) {
+	var valErr *validation.ValidationError
+	switch {
+	case errors.As(err, &valErr):
+		validation.RespondWithValidationError(w, valErr)
+	case errors.Is(err, weeks.ErrStatusChanged):
+		validation.RespondWithError(w, http.StatusConflict, "Week status changed, please reload and try again", "STATUS_CONFLICT", nil)
+	default:
+		validation.RespondWithError(w, http.StatusInternalServerError, "Error updating week status", "DATABASE_ERROR", nil)
+	}
+}
+
 func CreateWeek(a *app.App) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		claims, ok := middleware.GetUserFromContext(r)
@@ -82,10 +100,11 @@ func CreateWeek(a *app.App) http.HandlerFunc {
 		}
 
 		week := models.Week{
-			SeasonID:   req.SeasonID,
-			WeekNumber: req.WeekNumber,
-			Name:       req.Name,
-			Status:     "creating",
+			SeasonID:     req.SeasonID,
+			WeekNumber:   req.WeekNumber,
+			Name:         req.Name,
+			Status:       "creating",
+			IsPostseason: req.IsPostseason,
 		}
 
 		if err := a.DB.Create(&week).Error; err != nil {
@@ -160,6 +179,7 @@ func UpdateWeek(a *app.App) http.HandlerFunc {
 		week.SeasonID = req.SeasonID
 		week.WeekNumber = req.WeekNumber
 		week.Name = req.Name
+		week.IsPostseason = req.IsPostseason
 
 		if err := a.DB.Save(&week).Error; err != nil {
 			validation.RespondWithError(w, http.StatusInternalServerError, "Error updating week", "DATABASE_ERROR", nil)
@@ -222,12 +242,15 @@ func OpenWeekForPicks(a *app.App) http.HandlerFunc {
 			return
 		}
 
-		// Update week status and pick deadline
-		week.Status = "picking"
-		week.PickDeadline = &pickDeadline
-
-		if err := a.DB.Save(&week).Error; err != nil {
-			validation.RespondWithError(w, http.StatusInternalServerError, "Error updating week status", "DATABASE_ERROR", nil)
+		// Update pick deadline and week status
+		err := a.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(week).Update("pick_deadline", pickDeadline).Error; err != nil {
+				return err
+			}
+			return weeks.Transition(tx, week, "picking", weeks.UserActor(claims.UserID))
+		})
+		if err != nil {
+			respondTransitionError(w, err)
 			return
 		}
 
@@ -259,10 +282,17 @@ func LockWeek(a *app.App) http.HandlerFunc {
 		}
 
 		// Update week status
-		week.Status = "scoring"
-
-		if err := a.DB.Save(&week).Error; err != nil {
-			validation.RespondWithError(w, http.StatusInternalServerError, "Error updating week status", "DATABASE_ERROR", nil)
+		// Games members missed are filled in by the league's auto-pick policy as the week locks
+		err := a.DB.Transaction(func(tx *gorm.DB) error {
+			actor := weeks.UserActor(claims.UserID)
+			if err := weeks.Transition(tx, week, "scoring", actor); err != nil {
+				return err
+			}
+			_, err := picks.AutoPick(tx, week, actor)
+			return err
+		})
+		if err != nil {
+			respondTransitionError(w, err)
 			return
 		}
 
@@ -299,25 +329,105 @@ func CompleteWeek(a *app.App) http.HandlerFunc {
 			return
 		}
 
-		// Verify all games are final
+		// Verify all games are final, or cancelled with their picks voided
 		for _, game := range week.Games {
-			if !game.IsFinal {
+			if !game.IsFinal && game.Status != models.GameStatusCancelled {
 				validation.RespondWithError(w, http.StatusBadRequest, "Cannot complete week", "GAMES_NOT_FINAL", map[string]string{
-					"games": "All games must be marked as final before completing the week",
+					"games": "All games must be final or cancelled before completing the week; move postponed games to another week",
 				})
 				return
 			}
 		}
 
 		// Update week status
-		week.Status = "finished"
+		err := a.DB.Transaction(func(tx *gorm.DB) error {
+			return weeks.Transition(tx, week, "finished", weeks.UserActor(claims.UserID))
+		})
+		if err != nil {
+			respondTransitionError(w, err)
+			return
+		}
 
-		if err := a.DB.Save(&week).Error; err != nil {
-			validation.RespondWithError(w, http.StatusInternalServerError, "Error updating week status", "DATABASE_ERROR", nil)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(week)
+	}
+}
+
+// SetTiebreakerGameRequest is the request body for choosing a week's tiebreaker game
+type SetTiebreakerGameRequest struct {
+	GameID *uint `json:"game_id"` // null clears the tiebreaker game
+}
+
+// SetTiebreakerGame returns a handler for flagging the game whose combined score members guess to break ties
+// The game can only be changed before the week locks, since guesses lock with it
+func SetTiebreakerGame(a *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := middleware.GetUserFromContext(r)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		weekID := chi.URLParam(r, "id")
+
+		week, ok := verifyWeekPermission(a, w, claims, weekID)
+		if !ok {
+			return // error already sent by verifyWeekPermission
+		}
+
+		if week.Status != "creating" && week.Status != "picking" {
+			validation.RespondWithError(w, http.StatusBadRequest, "Cannot change tiebreaker game", "INVALID_STATUS", map[string]string{
+				"status": "The tiebreaker game can only be changed before the week locks",
+			})
+			return
+		}
+
+		var req SetTiebreakerGameRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			validation.RespondWithError(w, http.StatusBadRequest, "Invalid request body", "INVALID_JSON", nil)
+			return
+		}
+
+		if req.GameID != nil {
+			var game models.Game
+			if err := a.DB.Where("week_id = ?", week.ID).First(&game, *req.GameID).Error; err != nil {
+				validation.RespondWithError(w, http.StatusBadRequest, "Game not found in this week", "GAME_NOT_FOUND", map[string]string{
+					"game_id": "The tiebreaker game must be one of the week's games",
+				})
+				return
+			}
+			if game.Status == models.GameStatusPostponed || game.Status == models.GameStatusCancelled {
+				validation.RespondWithError(w, http.StatusBadRequest, "Game has been "+game.Status, "GAME_NOT_PLAYED", nil)
+				return
+			}
+		}
+
+		if err := a.DB.Model(week).Update("tiebreaker_game_id", req.GameID).Error; err != nil {
+			validation.RespondWithError(w, http.StatusInternalServerError, "Error updating week", "DATABASE_ERROR", nil)
 			return
 		}
+		week.TiebreakerGameID = req.GameID
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(week)
 	}
 }
+
+// GetWeekTransitions returns a handler for a week's status history, including scheduler locks
+func GetWeekTransitions(a *app.App) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		weekID := chi.URLParam(r, "id")
+
+		var transitions []models.WeekTransition
+		if err := a.DB.Where("week_id = ?", weekID).
+			Preload("User").
+			Order("created_at ASC, id ASC").
+			Find(&transitions).Error; err != nil {
+			http.Error(w, "Error fetching week history", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(transitions)
+	}
+}
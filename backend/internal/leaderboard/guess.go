@@ -0,0 +1,122 @@
+package leaderboard
+
+import (
+	"github.com/ckinger23/mountaintop/internal/models"
+	"gorm.io/gorm"
+)
+
+// guessResult is how a user's total-points guess compared with the tiebreaker game's actual total
+type guessResult struct {
+	total int
+	miss  int  // Points away from the actual total
+	over  bool // Went over, which only counts against the guess under the without-going-over rule
+}
+
+// weekGuesses scores a league's guesses on each week's tiebreaker game, keyed by week and then user
+// Weeks without a tiebreaker game, or whose tiebreaker game isn't final yet, are left out
+func weekGuesses(db *gorm.DB, leagueID uint, weeks []models.Week, rule string) (map[uint]map[uint]guessResult, error) {
+	gameWeeks := make(map[uint]uint) // tiebreaker game -> week
+	var gameIDs []uint
+	for _, week := range weeks {
+		if week.TiebreakerGameID != nil {
+			gameWeeks[*week.TiebreakerGameID] = week.ID
+			gameIDs = append(gameIDs, *week.TiebreakerGameID)
+		}
+	}
+	results := make(map[uint]map[uint]guessResult)
+	if len(gameIDs) == 0 {
+		return results, nil
+	}
+
+	var games []models.Game
+	if err := db.Where("id IN ? AND is_final = ?", gameIDs, true).Find(&games).Error; err != nil {
+		return nil, err
+	}
+	actual := make(map[uint]int) // week -> combined score
+	var weekIDs []uint
+	for _, game := range games {
+		if game.HomeScore == nil || game.AwayScore == nil {
+			continue
+		}
+		weekID := gameWeeks[game.ID]
+		actual[weekID] = *game.HomeScore + *game.AwayScore
+		weekIDs = append(weekIDs, weekID)
+	}
+	if len(weekIDs) == 0 {
+		return results, nil
+	}
+
+	var guesses []models.TiebreakerGuess
+	if err := db.Where("league_id = ? AND week_id IN ?", leagueID, weekIDs).Find(&guesses).Error; err != nil {
+		return nil, err
+	}
+	for _, guess := range guesses {
+		if results[guess.WeekID] == nil {
+			results[guess.WeekID] = make(map[uint]guessResult)
+		}
+		result := guessResult{total: guess.TotalPoints, miss: actual[guess.WeekID] - guess.TotalPoints}
+		if result.miss < 0 {
+			result.miss = -result.miss
+			result.over = rule != models.GuessClosest
+		}
+		results[guess.WeekID][guess.UserID] = result
+	}
+	return results, nil
+}
+
+// loadGuesses fills in each entry's guess on the tiebreaker game of the latest week it's final for
+// Guesses belong to a league's weeks, so they're only loaded for league leaderboards
+func (q *Query) loadGuesses(entries []models.LeaderboardEntry) error {
+	if q.leagueID == nil {
+		return nil
+	}
+
+	query := q.db.Joins("JOIN seasons ON seasons.id = weeks.season_id").
+		Where("seasons.league_id = ? AND weeks.tiebreaker_game_id IS NOT NULL", *q.leagueID)
+	if q.seasonID != nil {
+		query = query.Where("weeks.season_id = ?", *q.seasonID)
+	}
+	var weeks []models.Week
+	if err := query.Order("seasons.year DESC, weeks.week_number DESC, weeks.id DESC").Find(&weeks).Error; err != nil {
+		return err
+	}
+
+	results, err := weekGuesses(q.db, *q.leagueID, weeks, q.guessRule)
+	if err != nil {
+		return err
+	}
+	for _, week := range weeks {
+		guesses, ok := results[week.ID]
+		if !ok {
+			continue
+		}
+		for i := range entries {
+			if result, ok := guesses[entries[i].UserID]; ok {
+				entries[i].TotalGuess = &result.total
+				entries[i].GuessMiss = &result.miss
+				entries[i].GuessOver = result.over
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// compareGuess compares two guesses, returning > 0 when a's is better
+// Users without a guess go last, then guesses that went over, then the smallest miss wins
+func compareGuess(aMiss *int, aOver bool, bMiss *int, bOver bool) int {
+	switch {
+	case aMiss == nil && bMiss == nil:
+		return 0
+	case aMiss == nil:
+		return -1
+	case bMiss == nil:
+		return 1
+	case aOver != bOver:
+		if aOver {
+			return -1
+		}
+		return 1
+	}
+	return *bMiss - *aMiss
+}
@@ -7,9 +7,12 @@ import (
 
 // Query builds and executes a leaderboard query with optional season and league filters
 type Query struct {
-	db       *gorm.DB
-	seasonID *uint
-	leagueID *uint
+	db           *gorm.DB
+	seasonID     *uint
+	leagueID     *uint
+	tiebreakers  []string
+	rankingStyle string
+	guessRule    string
 }
 
 // NewQuery creates a new leaderboard query builder
@@ -29,35 +32,59 @@ func (q *Query) ForLeague(leagueID uint) *Query {
 	return q
 }
 
-// Execute runs the leaderboard query and returns results ordered by total points
+// WithTiebreakers breaks ties on points with each tiebreaker in turn (models.Tiebreaker*)
+func (q *Query) WithTiebreakers(tiebreakers []string) *Query {
+	q.tiebreakers = tiebreakers
+	return q
+}
+
+// WithRanking sets how users that stay tied are ranked: models.RankingStandard (the default) or models.RankingDense
+func (q *Query) WithRanking(style string) *Query {
+	q.rankingStyle = style
+	return q
+}
+
+// WithGuessRule sets how total-points guesses are compared: models.GuessWithoutGoingOver (the default) or models.GuessClosest
+func (q *Query) WithGuessRule(rule string) *Query {
+	q.guessRule = rule
+	return q
+}
+
+// Execute runs the leaderboard query and returns results ranked by total points and then the tiebreakers
+// Totals are read from the standings table, which RefreshWeek and RefreshMembers keep up to date as picks are saved and graded
 func (q *Query) Execute() ([]models.LeaderboardEntry, error) {
 	var results []models.LeaderboardEntry
 
-	// Start with base query selecting from users
-	// Note: We count total correct picks (spread + over/under), but total_picks is number of games
-	// Each game can contribute 0-2 points (1 for spread, 1 for over/under)
-	query := q.db.Table("users u").
+	// Start from league memberships so only current members are ranked, including members with no picks yet.
+	// Totals come from the standings table, one row per league, week and user, kept current as picks are graded.
+	// Picks only count in the league they were made in, so a member's standings in a league they've left don't count.
+	// Note: We count correct and pushed picks per component (spread + over/under), but total_picks is number of games
+	// Win percentage is wins / (wins + losses); pushes and voids are excluded
+	query := q.db.Table("league_memberships m").
 		Select(`
 			u.id as user_id,
 			u.username,
 			u.display_name,
-			COALESCE(SUM(p.points_earned), 0) as total_points,
-			COALESCE(SUM(CASE WHEN p.spread_correct = 1 THEN 1 ELSE 0 END) + SUM(CASE WHEN p.over_under_correct = 1 THEN 1 ELSE 0 END), 0) as correct_picks,
-			COUNT(p.id) as total_picks,
-			COALESCE(CAST(SUM(p.points_earned) AS FLOAT) / NULLIF(COUNT(p.id) * 2, 0), 0) as win_pct
+			COALESCE(SUM(s.points), 0) as total_points,
+			COALESCE(SUM(s.correct_picks), 0) as correct_picks,
+			COALESCE(SUM(s.push_picks), 0) as push_picks,
+			COALESCE(SUM(s.spread_wins), 0) as spread_wins,
+			COALESCE(SUM(s.total_picks), 0) as total_picks,
+			COALESCE(CAST(SUM(s.correct_picks) AS FLOAT) / NULLIF(SUM(s.decided_picks), 0), 0) as win_pct
 		`).
-		Joins("LEFT JOIN picks p ON u.id = p.user_id").
-		Joins("LEFT JOIN games g ON p.game_id = g.id").
-		Joins("LEFT JOIN weeks w ON g.week_id = w.id")
+		Joins("JOIN users u ON u.id = m.user_id AND u.deleted_at IS NULL").
+		Where("m.deleted_at IS NULL")
 
-	// Apply season filter if specified
+	// Apply season filter if specified; it goes in the join so members without picks that season still get a row
 	if q.seasonID != nil {
-		query = query.Where("w.season_id = ? OR p.id IS NULL", *q.seasonID)
+		query = query.Joins("LEFT JOIN standings s ON s.user_id = m.user_id AND s.league_id = m.league_id AND s.season_id = ?", *q.seasonID)
+	} else {
+		query = query.Joins("LEFT JOIN standings s ON s.user_id = m.user_id AND s.league_id = m.league_id")
 	}
 
-	// Apply league filter if specified
+	// Apply league filter if specified; without one, members of any league are ranked on all their leagues' picks
 	if q.leagueID != nil {
-		query = query.Where("p.league_id = ? OR p.id IS NULL", *q.leagueID)
+		query = query.Where("m.league_id = ?", *q.leagueID)
 	}
 
 	// Group by user and order by points
@@ -67,6 +94,16 @@ func (q *Query) Execute() ([]models.LeaderboardEntry, error) {
 	if err := query.Scan(&results).Error; err != nil {
 		return nil, err
 	}
+	if q.leagueID != nil {
+		for i := range results {
+			results[i].LeagueID = *q.leagueID
+		}
+	}
+
+	if err := q.loadTiebreakValues(results); err != nil {
+		return nil, err
+	}
+	Rank(results, q.tiebreakers, q.rankingStyle)
 
 	return results, nil
 }
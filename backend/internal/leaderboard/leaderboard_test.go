@@ -27,6 +27,8 @@ func setupTestDB(t *testing.T) *gorm.DB {
 		&models.Team{},
 		&models.Game{},
 		&models.Pick{},
+		&models.TiebreakerGuess{},
+		&models.Standing{},
 	)
 	if err != nil {
 		t.Fatalf("Failed to migrate test database: %v", err)
@@ -148,16 +150,14 @@ func seedTestData(t *testing.T, db *gorm.DB) uint {
 
 	// Create picks for 2024
 	// Alice: 2 correct picks
-	spreadCorrect1 := true
-	overUnderCorrect1 := false
 	db.Create(&models.Pick{
 		LeagueID:         league.ID,
 		UserID:           alice.ID,
 		GameID:           game1_2024.ID,
 		PickedTeamID:     teams[0].ID,
 		PickedOverUnder:  "over",
-		SpreadCorrect:    &spreadCorrect1,
-		OverUnderCorrect: &overUnderCorrect1,
+		SpreadOutcome:    models.PickOutcomeWin,
+		OverUnderOutcome: models.PickOutcomeLoss,
 		PointsEarned:     1,
 	})
 	db.Create(&models.Pick{
@@ -166,22 +166,20 @@ func seedTestData(t *testing.T, db *gorm.DB) uint {
 		GameID:           game2_2024.ID,
 		PickedTeamID:     teams[3].ID,
 		PickedOverUnder:  "under",
-		SpreadCorrect:    &spreadCorrect1,
-		OverUnderCorrect: &overUnderCorrect1,
+		SpreadOutcome:    models.PickOutcomeWin,
+		OverUnderOutcome: models.PickOutcomeLoss,
 		PointsEarned:     1,
 	})
 
 	// Bob: 1 correct, 1 incorrect
-	spreadCorrect2 := true
-	spreadIncorrect := false
 	db.Create(&models.Pick{
 		LeagueID:         league.ID,
 		UserID:           bob.ID,
 		GameID:           game1_2024.ID,
 		PickedTeamID:     teams[0].ID,
 		PickedOverUnder:  "over",
-		SpreadCorrect:    &spreadCorrect2,
-		OverUnderCorrect: &overUnderCorrect1,
+		SpreadOutcome:    models.PickOutcomeWin,
+		OverUnderOutcome: models.PickOutcomeLoss,
 		PointsEarned:     1,
 	})
 	db.Create(&models.Pick{
@@ -190,41 +188,47 @@ func seedTestData(t *testing.T, db *gorm.DB) uint {
 		GameID:           game2_2024.ID,
 		PickedTeamID:     teams[2].ID,
 		PickedOverUnder:  "under",
-		SpreadCorrect:    &spreadIncorrect,
-		OverUnderCorrect: &overUnderCorrect1,
+		SpreadOutcome:    models.PickOutcomeLoss,
+		OverUnderOutcome: models.PickOutcomeLoss,
 		PointsEarned:     0,
 	})
 
 	// Create picks for 2025
 	// Alice: 1 correct pick
-	spreadCorrect3 := true
 	db.Create(&models.Pick{
 		LeagueID:         league.ID,
 		UserID:           alice.ID,
 		GameID:           game1_2025.ID,
 		PickedTeamID:     teams[0].ID,
 		PickedOverUnder:  "over",
-		SpreadCorrect:    &spreadCorrect3,
-		OverUnderCorrect: &overUnderCorrect1,
+		SpreadOutcome:    models.PickOutcomeWin,
+		OverUnderOutcome: models.PickOutcomeLoss,
 		PointsEarned:     1,
 	})
 
 	// Charlie: 0 correct picks in 2025
-	spreadIncorrect2 := false
 	db.Create(&models.Pick{
 		LeagueID:         league.ID,
 		UserID:           charlie.ID,
 		GameID:           game1_2025.ID,
 		PickedTeamID:     teams[1].ID,
 		PickedOverUnder:  "under",
-		SpreadCorrect:    &spreadIncorrect2,
-		OverUnderCorrect: &overUnderCorrect1,
+		SpreadOutcome:    models.PickOutcomeLoss,
+		OverUnderOutcome: models.PickOutcomeLoss,
 		PointsEarned:     0,
 	})
 
+	rebuildStandings(t, db)
 	return league.ID
 }
 
+// rebuildStandings brings the standings table up to date with picks written straight to the database
+func rebuildStandings(t *testing.T, db *gorm.DB) {
+	if _, err := RebuildStandings(db); err != nil {
+		t.Fatalf("Failed to rebuild standings: %v", err)
+	}
+}
+
 func TestGetLeaderboard_AllSeasons(t *testing.T) {
 	db := setupTestDB(t)
 	leagueID := seedTestData(t, db)
@@ -267,7 +271,7 @@ func TestGetLeaderboard_FilteredBySeason(t *testing.T) {
 	entries, err := GetLeaderboard(db, &season2024.ID, &leagueID)
 
 	assert.NoError(t, err)
-	assert.Len(t, entries, 2, "Should return only users with picks in 2024")
+	assert.Len(t, entries, 3, "Should return every member, including those without picks in 2024")
 
 	// Alice should be first for 2024 (2 points - 2 spread correct out of 4 possible)
 	assert.Equal(t, "alice", entries[0].Username)
@@ -282,6 +286,10 @@ func TestGetLeaderboard_FilteredBySeason(t *testing.T) {
 	assert.Equal(t, 1, entries[1].CorrectPicks)
 	assert.Equal(t, 2, entries[1].TotalPicks) // 2 games picked
 	assert.InDelta(t, 0.25, entries[1].WinPct, 0.01) // 1 point / (2 games * 2) = 0.25
+
+	// Charlie only picked in 2025
+	assert.Equal(t, "charlie", entries[2].Username)
+	assert.Equal(t, 0, entries[2].TotalPicks)
 }
 
 func TestGetLeaderboard_2025Season(t *testing.T) {
@@ -295,7 +303,7 @@ func TestGetLeaderboard_2025Season(t *testing.T) {
 	entries, err := GetLeaderboard(db, &season2025.ID, &leagueID)
 
 	assert.NoError(t, err)
-	assert.Len(t, entries, 2, "Should return only users with picks in 2025")
+	assert.Len(t, entries, 3, "Should return every member, including those without picks in 2025")
 
 	// Alice should be first for 2025 (1 point - 1 spread correct out of 2 possible)
 	assert.Equal(t, "alice", entries[0].Username)
@@ -303,11 +311,13 @@ func TestGetLeaderboard_2025Season(t *testing.T) {
 	assert.Equal(t, 1, entries[0].CorrectPicks)
 	assert.Equal(t, 1, entries[0].TotalPicks) // 1 game picked
 
-	// Charlie should be second (0 points but has a pick)
-	assert.Equal(t, "charlie", entries[1].Username)
-	assert.Equal(t, 0, entries[1].TotalPoints)
-	assert.Equal(t, 0, entries[1].CorrectPicks)
-	assert.Equal(t, 1, entries[1].TotalPicks) // 1 game picked
+	// Bob and Charlie tie on 0 points and are listed by username
+	assert.Equal(t, "bob", entries[1].Username)
+	assert.Equal(t, 0, entries[1].TotalPicks)
+	assert.Equal(t, "charlie", entries[2].Username)
+	assert.Equal(t, 0, entries[2].TotalPoints)
+	assert.Equal(t, 0, entries[2].CorrectPicks)
+	assert.Equal(t, 1, entries[2].TotalPicks) // 1 game picked
 }
 
 func TestQueryBuilder_Chaining(t *testing.T) {
@@ -322,7 +332,7 @@ func TestQueryBuilder_Chaining(t *testing.T) {
 	entries, err := query.Execute()
 
 	assert.NoError(t, err)
-	assert.Len(t, entries, 2, "Should return only users with picks in 2024")
+	assert.Len(t, entries, 3)
 	assert.Equal(t, "alice", entries[0].Username)
 	assert.Equal(t, 2, entries[0].TotalPoints)
 }
@@ -384,3 +394,63 @@ func TestGetLeaderboard_UsersWithNoPicks(t *testing.T) {
 		assert.Equal(t, 0.0, entry.WinPct)
 	}
 }
+
+func TestGetLeaderboard_MembersOnly(t *testing.T) {
+	db := setupTestDB(t)
+	leagueID := seedTestData(t, db)
+
+	var bob, charlie models.User
+	db.Where("username = ?", "bob").First(&bob)
+	db.Where("username = ?", "charlie").First(&charlie)
+
+	// Dave never joined the league, Charlie left it, and one of Bob's picks was deleted
+	dave := models.User{Username: "dave", Email: "dave@example.com", DisplayName: "Dave", PasswordHash: "hash4"}
+	db.Create(&dave)
+	db.Where("league_id = ? AND user_id = ?", leagueID, charlie.ID).Delete(&models.LeagueMembership{})
+	db.Where("user_id = ? AND points_earned = ?", bob.ID, 1).Delete(&models.Pick{})
+	rebuildStandings(t, db)
+
+	entries, err := GetLeaderboard(db, nil, &leagueID)
+
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, "alice", entries[0].Username)
+		assert.Equal(t, leagueID, entries[0].LeagueID)
+		assert.Equal(t, "bob", entries[1].Username)
+		assert.Equal(t, 0, entries[1].TotalPoints)
+		assert.Equal(t, 1, entries[1].TotalPicks)
+	}
+
+	// Without a league, only users in some league are ranked
+	entries, err = GetLeaderboard(db, nil, nil)
+
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestGetLeaderboard_PushesCountedSeparately(t *testing.T) {
+	db := setupTestDB(t)
+	leagueID := seedTestData(t, db)
+
+	var alice models.User
+	db.Where("username = ?", "alice").First(&alice)
+
+	// Turn one of Alice's over/under losses into a push
+	var pick models.Pick
+	db.Where("user_id = ?", alice.ID).First(&pick)
+	db.Model(&pick).Update("over_under_outcome", models.PickOutcomePush)
+	rebuildStandings(t, db)
+
+	entries, err := GetLeaderboard(db, nil, &leagueID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", entries[0].Username)
+	assert.Equal(t, 3, entries[0].CorrectPicks)
+	assert.Equal(t, 1, entries[0].PushPicks)
+	assert.Equal(t, 3, entries[0].TotalPicks)
+	assert.InDelta(t, 0.6, entries[0].WinPct, 0.01) // 3 wins / (3 wins + 2 losses), push excluded
+
+	// Bob has no pushes
+	assert.Equal(t, "bob", entries[1].Username)
+	assert.Equal(t, 0, entries[1].PushPicks)
+}
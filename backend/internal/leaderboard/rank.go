@@ -0,0 +1,154 @@
+package leaderboard
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+)
+
+// pickPoints is one pick's points, for comparing users game by game
+type pickPoints struct {
+	UserID       uint
+	GameID       uint
+	PointsEarned int
+	CreatedAt    time.Time
+}
+
+// loadTiebreakValues fills in the head-to-head, earliest-submission and total-guess values the tiebreakers need
+// Picks are filtered the same way as the leaderboard, so only picks made in a league the user still belongs to count
+func (q *Query) loadTiebreakValues(entries []models.LeaderboardEntry) error {
+	needed := false
+	for _, tiebreaker := range q.tiebreakers {
+		switch tiebreaker {
+		case models.TiebreakerHeadToHead, models.TiebreakerEarliest:
+			needed = true
+		case models.TiebreakerTotalGuess:
+			if err := q.loadGuesses(entries); err != nil {
+				return err
+			}
+		}
+	}
+	if !needed {
+		return nil
+	}
+
+	query := q.db.Table("picks p").
+		Select("p.user_id, p.game_id, p.points_earned, p.created_at").
+		Joins("JOIN games g ON p.game_id = g.id").
+		Joins("JOIN weeks w ON g.week_id = w.id").
+		Joins("JOIN league_memberships m ON m.user_id = p.user_id AND m.league_id = p.league_id AND m.deleted_at IS NULL").
+		Where("p.deleted_at IS NULL")
+	if q.seasonID != nil {
+		query = query.Where("w.season_id = ?", *q.seasonID)
+	}
+	if q.leagueID != nil {
+		query = query.Where("p.league_id = ?", *q.leagueID)
+	}
+	var picks []pickPoints
+	if err := query.Scan(&picks).Error; err != nil {
+		return err
+	}
+
+	gamePoints := make(map[uint]map[uint]int) // user -> game -> points
+	firstPick := make(map[uint]time.Time)
+	for _, pick := range picks {
+		if gamePoints[pick.UserID] == nil {
+			gamePoints[pick.UserID] = make(map[uint]int)
+		}
+		gamePoints[pick.UserID][pick.GameID] += pick.PointsEarned
+		if first, ok := firstPick[pick.UserID]; !ok || pick.CreatedAt.Before(first) {
+			firstPick[pick.UserID] = pick.CreatedAt
+		}
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+		if first, ok := firstPick[entry.UserID]; ok {
+			entry.FirstPickAt = &first
+		}
+
+		// Head-to-head only counts against users on the same points
+		entry.HeadToHeadWins = 0
+		for _, other := range entries {
+			if other.UserID == entry.UserID || other.TotalPoints != entry.TotalPoints {
+				continue
+			}
+			mine, theirs := 0, 0
+			for gameID, points := range gamePoints[entry.UserID] {
+				if otherPoints, ok := gamePoints[other.UserID][gameID]; ok {
+					mine += points
+					theirs += otherPoints
+				}
+			}
+			if mine > theirs {
+				entry.HeadToHeadWins++
+			}
+		}
+	}
+	return nil
+}
+
+// compareTiebreaker compares two entries on one tiebreaker, returning > 0 when a ranks ahead of b
+func compareTiebreaker(tiebreaker string, a, b *models.LeaderboardEntry) int {
+	switch tiebreaker {
+	case models.TiebreakerCorrectPicks:
+		return a.CorrectPicks - b.CorrectPicks
+	case models.TiebreakerSpreadWins:
+		return a.SpreadWins - b.SpreadWins
+	case models.TiebreakerHeadToHead:
+		return a.HeadToHeadWins - b.HeadToHeadWins
+	case models.TiebreakerEarliest:
+		// Users without picks go last
+		switch {
+		case a.FirstPickAt == nil && b.FirstPickAt == nil:
+			return 0
+		case a.FirstPickAt == nil:
+			return -1
+		case b.FirstPickAt == nil:
+			return 1
+		}
+		return b.FirstPickAt.Compare(*a.FirstPickAt)
+	case models.TiebreakerTotalGuess:
+		return compareGuess(a.GuessMiss, a.GuessOver, b.GuessMiss, b.GuessOver)
+	}
+	return 0
+}
+
+// Rank orders entries by points and then each tiebreaker in turn, and assigns their ranks
+// Users still tied after every tiebreaker share a rank and are listed by username so the order is stable.
+// style is models.RankingStandard (1, 2, 2, 4) or models.RankingDense (1, 2, 2, 3)
+func Rank(entries []models.LeaderboardEntry, tiebreakers []string, style string) {
+	compare := func(a, b *models.LeaderboardEntry) int {
+		if a.TotalPoints != b.TotalPoints {
+			return a.TotalPoints - b.TotalPoints
+		}
+		for _, tiebreaker := range tiebreakers {
+			if c := compareTiebreaker(tiebreaker, a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if c := compare(&entries[i], &entries[j]); c != 0 {
+			return c > 0
+		}
+		if entries[i].Username != entries[j].Username {
+			return entries[i].Username < entries[j].Username
+		}
+		return entries[i].UserID < entries[j].UserID
+	})
+
+	for i := range entries {
+		switch {
+		case i > 0 && compare(&entries[i], &entries[i-1]) == 0:
+			entries[i].Rank = entries[i-1].Rank
+		case style == models.RankingDense && i > 0:
+			entries[i].Rank = entries[i-1].Rank + 1
+		default:
+			entries[i].Rank = i + 1
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package leaderboard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRank(t *testing.T) {
+	early := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+	late := early.Add(time.Hour)
+	one, three := 1, 3
+
+	entries := func() []models.LeaderboardEntry {
+		return []models.LeaderboardEntry{
+			{UserID: 1, Username: "dana", TotalPoints: 5, CorrectPicks: 5, SpreadWins: 3, FirstPickAt: &late, GuessMiss: &three},
+			{UserID: 2, Username: "carl", TotalPoints: 8, CorrectPicks: 8, SpreadWins: 4},
+			{UserID: 3, Username: "beth", TotalPoints: 5, CorrectPicks: 5, SpreadWins: 3, FirstPickAt: &early, GuessMiss: &one, GuessOver: true},
+			{UserID: 4, Username: "abby", TotalPoints: 5, CorrectPicks: 6, SpreadWins: 2},
+			{UserID: 5, Username: "eric", TotalPoints: 2, CorrectPicks: 2, SpreadWins: 1},
+		}
+	}
+	usernames := func(entries []models.LeaderboardEntry) []string {
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Username)
+		}
+		return names
+	}
+	ranks := func(entries []models.LeaderboardEntry) []int {
+		var ranks []int
+		for _, entry := range entries {
+			ranks = append(ranks, entry.Rank)
+		}
+		return ranks
+	}
+
+	tests := []struct {
+		name        string
+		tiebreakers []string
+		style       string
+		wantOrder   []string
+		wantRanks   []int
+	}{
+		{
+			name:      "ties share a rank and are listed by username",
+			wantOrder: []string{"carl", "abby", "beth", "dana", "eric"},
+			wantRanks: []int{1, 2, 2, 2, 5},
+		},
+		{
+			name:      "dense ranking doesn't skip ranks",
+			style:     models.RankingDense,
+			wantOrder: []string{"carl", "abby", "beth", "dana", "eric"},
+			wantRanks: []int{1, 2, 2, 2, 3},
+		},
+		{
+			name:        "tiebreakers apply in order",
+			tiebreakers: []string{models.TiebreakerSpreadWins, models.TiebreakerCorrectPicks},
+			wantOrder:   []string{"carl", "beth", "dana", "abby", "eric"},
+			wantRanks:   []int{1, 2, 2, 4, 5},
+		},
+		{
+			name:        "earliest submission ranks users without picks last",
+			tiebreakers: []string{models.TiebreakerEarliest},
+			wantOrder:   []string{"carl", "beth", "dana", "abby", "eric"},
+			wantRanks:   []int{1, 2, 3, 4, 5},
+		},
+		{
+			name:        "a guess that went over loses to one that didn't",
+			tiebreakers: []string{models.TiebreakerTotalGuess},
+			wantOrder:   []string{"carl", "dana", "beth", "abby", "eric"},
+			wantRanks:   []int{1, 2, 3, 4, 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranked := entries()
+			Rank(ranked, tt.tiebreakers, tt.style)
+			assert.Equal(t, tt.wantOrder, usernames(ranked))
+			assert.Equal(t, tt.wantRanks, ranks(ranked))
+		})
+	}
+}
+
+func TestExecute_HeadToHead(t *testing.T) {
+	db := setupTestDB(t)
+	leagueID := seedTestData(t, db)
+
+	var bob, charlie models.User
+	db.Where("username = ?", "bob").First(&bob)
+	db.Where("username = ?", "charlie").First(&charlie)
+
+	// Bob and Charlie finish level, but Charlie outscored Bob on the game they both picked
+	var game models.Game
+	db.Order("id DESC").First(&game)
+	db.Create(&models.Pick{LeagueID: leagueID, UserID: bob.ID, GameID: game.ID, PickedTeamID: game.AwayTeamID, PickedOverUnder: "under",
+		SpreadOutcome: models.PickOutcomeLoss, OverUnderOutcome: models.PickOutcomeLoss})
+	db.Model(&models.Pick{}).Where("user_id = ? AND game_id = ?", charlie.ID, game.ID).Update("points_earned", 1)
+	rebuildStandings(t, db)
+
+	entries, err := NewQuery(db).ForLeague(leagueID).
+		WithTiebreakers([]string{models.TiebreakerHeadToHead}).
+		Execute()
+
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 3) {
+		assert.Equal(t, "charlie", entries[1].Username)
+		assert.Equal(t, 1, entries[1].HeadToHeadWins)
+		assert.Equal(t, 2, entries[1].Rank)
+		assert.Equal(t, "bob", entries[2].Username)
+		assert.Equal(t, 0, entries[2].HeadToHeadWins)
+		assert.Equal(t, 3, entries[2].Rank)
+	}
+}
+
+func TestExecute_TotalGuess(t *testing.T) {
+	db := setupTestDB(t)
+	leagueID := seedTestData(t, db)
+
+	var alice, bob models.User
+	db.Where("username = ?", "alice").First(&alice)
+	db.Where("username = ?", "bob").First(&bob)
+
+	// Each season's week 1 uses its first game as the tiebreaker: 28-21 in 2024 and 42-38 in 2025
+	var weeks []models.Week
+	db.Joins("JOIN seasons ON seasons.id = weeks.season_id").Order("seasons.year").Find(&weeks)
+	for _, week := range weeks {
+		var game models.Game
+		db.Where("week_id = ?", week.ID).Order("id").First(&game)
+		db.Model(&week).Update("tiebreaker_game_id", game.ID)
+	}
+	db.Create(&models.TiebreakerGuess{LeagueID: leagueID, UserID: alice.ID, WeekID: weeks[0].ID, TotalPoints: 45})
+	db.Create(&models.TiebreakerGuess{LeagueID: leagueID, UserID: bob.ID, WeekID: weeks[0].ID, TotalPoints: 50})
+	db.Create(&models.TiebreakerGuess{LeagueID: leagueID, UserID: bob.ID, WeekID: weeks[1].ID, TotalPoints: 70})
+
+	// Standings use the guesses on the latest week's tiebreaker game
+	entries, err := NewQuery(db).ForLeague(leagueID).
+		WithTiebreakers([]string{models.TiebreakerTotalGuess}).
+		Execute()
+	assert.NoError(t, err)
+	for _, entry := range entries {
+		switch entry.UserID {
+		case bob.ID:
+			if assert.NotNil(t, entry.GuessMiss) {
+				assert.Equal(t, 70, *entry.TotalGuess)
+				assert.Equal(t, 10, *entry.GuessMiss)
+			}
+		case alice.ID:
+			assert.Nil(t, entry.GuessMiss)
+		}
+	}
+
+	// A season's standings use that season's latest week
+	entries, err = NewQuery(db).ForLeague(leagueID).ForSeason(weeks[0].SeasonID).
+		WithTiebreakers([]string{models.TiebreakerTotalGuess}).
+		Execute()
+	assert.NoError(t, err)
+	for _, entry := range entries {
+		if entry.UserID == alice.ID && assert.NotNil(t, entry.GuessMiss) {
+			assert.Equal(t, 4, *entry.GuessMiss)
+			assert.False(t, entry.GuessOver)
+		}
+		if entry.UserID == bob.ID && assert.NotNil(t, entry.GuessMiss) {
+			assert.Equal(t, 1, *entry.GuessMiss)
+			assert.True(t, entry.GuessOver)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package leaderboard
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"gorm.io/gorm"
+)
+
+// standingsInsert totals picks by league, week and user into the standings table; %s filters the picks
+const standingsInsert = `
+	INSERT INTO standings (created_at, league_id, season_id, week_id, user_id, points, correct_picks, push_picks, spread_wins, decided_picks, total_picks)
+	SELECT
+		?,
+		p.league_id,
+		w.season_id,
+		g.week_id,
+		p.user_id,
+		COALESCE(SUM(p.points_earned), 0),
+		SUM(CASE WHEN p.spread_outcome = 'win' THEN 1 ELSE 0 END) + SUM(CASE WHEN p.over_under_outcome = 'win' THEN 1 ELSE 0 END),
+		SUM(CASE WHEN p.spread_outcome = 'push' THEN 1 ELSE 0 END) + SUM(CASE WHEN p.over_under_outcome = 'push' THEN 1 ELSE 0 END),
+		SUM(CASE WHEN p.spread_outcome = 'win' THEN 1 ELSE 0 END),
+		SUM(CASE WHEN p.spread_outcome IN ('win', 'loss') THEN 1 ELSE 0 END) + SUM(CASE WHEN p.over_under_outcome IN ('win', 'loss') THEN 1 ELSE 0 END),
+		COUNT(p.id)
+	FROM picks p
+	JOIN games g ON p.game_id = g.id AND g.deleted_at IS NULL
+	JOIN weeks w ON g.week_id = w.id AND w.deleted_at IS NULL
+	WHERE p.deleted_at IS NULL AND %s
+	GROUP BY p.league_id, g.week_id, p.user_id`
+
+// RefreshWeek recomputes the standings rows for every league member with picks in a week
+// Call it whenever a week's picks are graded or voided, or a game moves in or out of it.
+// This function should be called within a transaction
+func RefreshWeek(tx *gorm.DB, weekID uint) error {
+	if err := tx.Where("week_id = ?", weekID).Delete(&models.Standing{}).Error; err != nil {
+		return err
+	}
+	return tx.Exec(fmt.Sprintf(standingsInsert, "g.week_id = ?"), time.Now(), weekID).Error
+}
+
+// RefreshMembers recomputes the standings rows of some of a league's members in one week
+// Call it once after saving picks, rather than per pick. With no userIDs every member of the league is refreshed.
+// This function should be called within a transaction
+func RefreshMembers(tx *gorm.DB, leagueID, weekID uint, userIDs ...uint) error {
+	filter := "g.week_id = ? AND p.league_id = ?"
+	del := tx.Where("week_id = ? AND league_id = ?", weekID, leagueID)
+	args := []interface{}{time.Now(), weekID, leagueID}
+	if len(userIDs) > 0 {
+		filter += " AND p.user_id IN ?"
+		del = del.Where("user_id IN ?", userIDs)
+		args = append(args, userIDs)
+	}
+
+	if err := del.Delete(&models.Standing{}).Error; err != nil {
+		return err
+	}
+	return tx.Exec(fmt.Sprintf(standingsInsert, filter), args...).Error
+}
+
+// RefreshGame recomputes the standings for the week a game is in
+// This function should be called within a transaction
+func RefreshGame(tx *gorm.DB, gameID uint) error {
+	var game models.Game
+	if err := tx.Select("id", "week_id").First(&game, gameID).Error; err != nil {
+		return err
+	}
+	return RefreshWeek(tx, game.WeekID)
+}
+
+// RebuildStandings replaces every standings row with totals recomputed from the picks
+// Used to repair the table; returns the number of rows written
+func RebuildStandings(db *gorm.DB) (int64, error) {
+	var rows int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&models.Standing{}).Error; err != nil {
+			return err
+		}
+		result := tx.Exec(fmt.Sprintf(standingsInsert, "1 = 1"), time.Now())
+		rows = result.RowsAffected
+		return result.Error
+	})
+	return rows, err
+}
@@ -0,0 +1,88 @@
+package leaderboard
+
+import (
+	"testing"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRebuildStandings(t *testing.T) {
+	db := setupTestDB(t)
+	seedTestData(t, db)
+
+	// One row per league, week and user with picks
+	rows, err := RebuildStandings(db)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), rows)
+
+	var alice models.User
+	db.Where("username = ?", "alice").First(&alice)
+	var standing models.Standing
+	db.Joins("JOIN seasons ON seasons.id = standings.season_id").
+		Where("standings.user_id = ? AND seasons.year = ?", alice.ID, 2024).
+		First(&standing)
+	assert.Equal(t, 2, standing.Points)
+	assert.Equal(t, 2, standing.CorrectPicks)
+	assert.Equal(t, 4, standing.DecidedPicks)
+	assert.Equal(t, 2, standing.TotalPicks)
+}
+
+func TestRefreshWeek(t *testing.T) {
+	db := setupTestDB(t)
+	seedTestData(t, db)
+
+	var bob, charlie models.User
+	db.Where("username = ?", "bob").First(&bob)
+	db.Where("username = ?", "charlie").First(&charlie)
+	var week2024, week2025 models.Week
+	db.Joins("JOIN seasons ON seasons.id = weeks.season_id").Where("seasons.year = ?", 2024).First(&week2024)
+	db.Joins("JOIN seasons ON seasons.id = weeks.season_id").Where("seasons.year = ?", 2025).First(&week2025)
+
+	// Bob's losing pick is regraded and Charlie's pick in the other week is deleted
+	db.Model(&models.Pick{}).Where("user_id = ? AND points_earned = ?", bob.ID, 0).Update("points_earned", 3)
+	db.Where("user_id = ?", charlie.ID).Delete(&models.Pick{})
+
+	assert.NoError(t, RefreshWeek(db, week2024.ID))
+
+	var standing models.Standing
+	db.Where("week_id = ? AND user_id = ?", week2024.ID, bob.ID).First(&standing)
+	assert.Equal(t, 4, standing.Points)
+
+	// Only the refreshed week changes
+	var count int64
+	db.Model(&models.Standing{}).Where("week_id = ? AND user_id = ?", week2025.ID, charlie.ID).Count(&count)
+	assert.Equal(t, int64(1), count)
+
+	assert.NoError(t, RefreshWeek(db, week2025.ID))
+	db.Model(&models.Standing{}).Where("week_id = ? AND user_id = ?", week2025.ID, charlie.ID).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestRefreshMembers(t *testing.T) {
+	db := setupTestDB(t)
+	leagueID := seedTestData(t, db)
+
+	var alice, bob models.User
+	db.Where("username = ?", "alice").First(&alice)
+	db.Where("username = ?", "bob").First(&bob)
+	var week models.Week
+	db.Joins("JOIN seasons ON seasons.id = weeks.season_id").Where("seasons.year = ?", 2024).First(&week)
+
+	// Both members' picks change but only Bob is refreshed
+	db.Model(&models.Pick{}).Where("user_id IN ?", []uint{alice.ID, bob.ID}).Update("points_earned", 5)
+
+	assert.NoError(t, RefreshMembers(db, leagueID, week.ID, bob.ID))
+
+	var bobStanding, aliceStanding models.Standing
+	db.Where("week_id = ? AND user_id = ?", week.ID, bob.ID).First(&bobStanding)
+	assert.Equal(t, 10, bobStanding.Points)
+	db.Where("week_id = ? AND user_id = ?", week.ID, alice.ID).First(&aliceStanding)
+	assert.Equal(t, 2, aliceStanding.Points)
+
+	// No users refreshes the whole league
+	assert.NoError(t, RefreshMembers(db, leagueID, week.ID))
+	aliceStanding = models.Standing{}
+	db.Where("week_id = ? AND user_id = ?", week.ID, alice.ID).First(&aliceStanding)
+	assert.Equal(t, 10, aliceStanding.Points)
+}
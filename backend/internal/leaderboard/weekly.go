@@ -0,0 +1,240 @@
+package leaderboard
+
+import (
+	"github.com/ckinger23/mountaintop/internal/models"
+	"gorm.io/gorm"
+)
+
+// WeekScore is one user's results for one week
+type WeekScore struct {
+	WeekID       uint `json:"week_id"`
+	UserID       uint `json:"user_id"`
+	Points       int  `json:"points"`
+	CorrectPicks int  `json:"correct_picks"` // Correct spread and over/under picks
+	SpreadWins   int  `json:"spread_wins"`
+	TotalPicks   int  `json:"total_picks"`
+
+	// Guess on the week's tiebreaker game, once the game is final
+	TotalGuess *int `gorm:"-" json:"total_guess,omitempty"`
+	GuessMiss  *int `gorm:"-" json:"guess_miss,omitempty"` // Points away from the actual total
+	GuessOver  bool `gorm:"-" json:"guess_over,omitempty"`
+}
+
+// WeekResult is a week's column in the weekly grid
+type WeekResult struct {
+	WeekID     uint   `json:"week_id"`
+	WeekNumber int    `json:"week_number"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Final      bool   `json:"final"`   // Week is finished, so its winners won't change
+	Winners    []uint `json:"winners"` // Empty until a game in the week is final; more than one when a tie survives every tiebreaker
+}
+
+// GridRow is one member's row in the weekly grid
+// Weeks lines up with Grid.Weeks
+type GridRow struct {
+	UserID      uint        `json:"user_id"`
+	Username    string      `json:"username"`
+	DisplayName string      `json:"display_name"`
+	TotalPoints int         `json:"total_points"`
+	Weeks       []WeekScore `gorm:"-" json:"weeks"`
+}
+
+// Grid is a league season's points for every member in every week
+type Grid struct {
+	LeagueID uint         `json:"league_id"`
+	SeasonID uint         `json:"season_id"`
+	Weeks    []WeekResult `json:"weeks"`
+	Rows     []GridRow    `json:"rows"`
+}
+
+// WeekScores returns each user's results per week for a league season
+// Only users with picks in a week have a score for it
+func WeekScores(db *gorm.DB, leagueID, seasonID uint) ([]WeekScore, error) {
+	var scores []WeekScore
+	err := db.Table("picks p").
+		Select(`
+			g.week_id,
+			p.user_id,
+			COALESCE(SUM(p.points_earned), 0) as points,
+			SUM(CASE WHEN p.spread_outcome = 'win' THEN 1 ELSE 0 END) + SUM(CASE WHEN p.over_under_outcome = 'win' THEN 1 ELSE 0 END) as correct_picks,
+			SUM(CASE WHEN p.spread_outcome = 'win' THEN 1 ELSE 0 END) as spread_wins,
+			COUNT(p.id) as total_picks
+		`).
+		Joins("JOIN games g ON p.game_id = g.id AND g.deleted_at IS NULL").
+		Joins("JOIN weeks w ON g.week_id = w.id AND w.deleted_at IS NULL").
+		Where("p.league_id = ? AND w.season_id = ? AND p.deleted_at IS NULL", leagueID, seasonID).
+		Group("g.week_id, p.user_id").
+		Order("g.week_id, p.user_id").
+		Scan(&scores).Error
+	return scores, err
+}
+
+// WeekWinners returns the users with the most points in a week
+// Ties are broken by each tiebreaker in turn; users still tied after the last one share the week.
+// A total guess only breaks a tie once the week's tiebreaker game is final.
+// Head-to-head and earliest submission only apply to season standings and are skipped. Users without picks can't win, so a week nobody picked has no winners
+func WeekWinners(scores []WeekScore, tiebreakers []string) []uint {
+	var candidates []WeekScore
+	for _, score := range scores {
+		if score.TotalPicks > 0 {
+			candidates = append(candidates, score)
+		}
+	}
+
+	candidates = leaders(candidates, func(s WeekScore) int { return s.Points })
+	for _, tiebreaker := range tiebreakers {
+		if len(candidates) < 2 {
+			break
+		}
+		switch tiebreaker {
+		case models.TiebreakerCorrectPicks:
+			candidates = leaders(candidates, func(s WeekScore) int { return s.CorrectPicks })
+		case models.TiebreakerSpreadWins:
+			candidates = leaders(candidates, func(s WeekScore) int { return s.SpreadWins })
+		case models.TiebreakerTotalGuess:
+			candidates = closestGuesses(candidates)
+		}
+	}
+
+	winners := make([]uint, 0, len(candidates))
+	for _, score := range candidates {
+		winners = append(winners, score.UserID)
+	}
+	return winners
+}
+
+// leaders returns the scores with the highest value
+func leaders(scores []WeekScore, value func(WeekScore) int) []WeekScore {
+	var best []WeekScore
+	for _, score := range scores {
+		switch {
+		case len(best) == 0 || value(score) > value(best[0]):
+			best = []WeekScore{score}
+		case value(score) == value(best[0]):
+			best = append(best, score)
+		}
+	}
+	return best
+}
+
+// closestGuesses returns the scores with the best guess on the week's tiebreaker game
+func closestGuesses(scores []WeekScore) []WeekScore {
+	var best []WeekScore
+	for _, score := range scores {
+		if len(best) == 0 {
+			best = []WeekScore{score}
+			continue
+		}
+		switch c := compareGuess(score.GuessMiss, score.GuessOver, best[0].GuessMiss, best[0].GuessOver); {
+		case c > 0:
+			best = []WeekScore{score}
+		case c == 0:
+			best = append(best, score)
+		}
+	}
+	return best
+}
+
+// WeeklyGrid builds the member x week grid for a league season, with each week's winners
+// Every member gets a row, including members with no picks. A week has no winners until one of its games is
+// final or the week is finished. guessRule is how guesses on each week's tiebreaker game are compared (models.Guess*)
+func WeeklyGrid(db *gorm.DB, leagueID, seasonID uint, tiebreakers []string, guessRule string) (*Grid, error) {
+	var weeks []models.Week
+	if err := db.Where("season_id = ?", seasonID).Order("week_number, id").Find(&weeks).Error; err != nil {
+		return nil, err
+	}
+
+	var rows []GridRow
+	if err := db.Table("league_memberships m").
+		Select("u.id as user_id, u.username, u.display_name").
+		Joins("JOIN users u ON u.id = m.user_id AND u.deleted_at IS NULL").
+		Where("m.league_id = ? AND m.deleted_at IS NULL", leagueID).
+		Order("u.id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	scores, err := WeekScores(db, leagueID, seasonID)
+	if err != nil {
+		return nil, err
+	}
+	guesses, err := weekGuesses(db, leagueID, weeks, guessRule)
+	if err != nil {
+		return nil, err
+	}
+
+	// Before any game is final every member is level, so nobody has won the week yet
+	var scoredWeekIDs []uint
+	if err := db.Model(&models.Game{}).
+		Joins("JOIN weeks ON weeks.id = games.week_id").
+		Where("weeks.season_id = ? AND games.is_final = ?", seasonID, true).
+		Distinct().Pluck("games.week_id", &scoredWeekIDs).Error; err != nil {
+		return nil, err
+	}
+	scored := make(map[uint]bool, len(scoredWeekIDs))
+	for _, id := range scoredWeekIDs {
+		scored[id] = true
+	}
+	byWeek := make(map[uint][]WeekScore)
+	byWeekUser := make(map[uint]map[uint]WeekScore)
+	for _, score := range scores {
+		if result, ok := guesses[score.WeekID][score.UserID]; ok {
+			score.TotalGuess = &result.total
+			score.GuessMiss = &result.miss
+			score.GuessOver = result.over
+		}
+		byWeek[score.WeekID] = append(byWeek[score.WeekID], score)
+		if byWeekUser[score.WeekID] == nil {
+			byWeekUser[score.WeekID] = make(map[uint]WeekScore)
+		}
+		byWeekUser[score.WeekID][score.UserID] = score
+	}
+
+	members := make(map[uint]bool)
+	for _, row := range rows {
+		members[row.UserID] = true
+	}
+
+	grid := &Grid{LeagueID: leagueID, SeasonID: seasonID, Weeks: make([]WeekResult, 0, len(weeks))}
+	for _, week := range weeks {
+		result := WeekResult{
+			WeekID:     week.ID,
+			WeekNumber: week.WeekNumber,
+			Name:       week.Name,
+			Status:     week.Status,
+			Final:      week.Status == "finished",
+			Winners:    []uint{},
+		}
+		if result.Final || scored[week.ID] {
+			// Only current members can win a week
+			var memberScores []WeekScore
+			for _, score := range byWeek[week.ID] {
+				if members[score.UserID] {
+					memberScores = append(memberScores, score)
+				}
+			}
+			result.Winners = WeekWinners(memberScores, tiebreakers)
+		}
+		grid.Weeks = append(grid.Weeks, result)
+	}
+
+	for i := range rows {
+		row := &rows[i]
+		row.Weeks = make([]WeekScore, 0, len(weeks))
+		for _, week := range weeks {
+			score, ok := byWeekUser[week.ID][row.UserID]
+			if !ok {
+				score = WeekScore{WeekID: week.ID, UserID: row.UserID}
+			}
+			row.TotalPoints += score.Points
+			row.Weeks = append(row.Weeks, score)
+		}
+	}
+	grid.Rows = rows
+	if grid.Rows == nil {
+		grid.Rows = []GridRow{}
+	}
+
+	return grid, nil
+}
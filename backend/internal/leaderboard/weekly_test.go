@@ -0,0 +1,176 @@
+package leaderboard
+
+import (
+	"testing"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeekWinners(t *testing.T) {
+	tests := []struct {
+		name        string
+		scores      []WeekScore
+		tiebreakers []string
+		want        []uint
+	}{
+		{
+			name:   "most points wins",
+			scores: []WeekScore{{UserID: 1, Points: 4, TotalPicks: 5}, {UserID: 2, Points: 6, TotalPicks: 5}},
+			want:   []uint{2},
+		},
+		{
+			name: "tiebreakers apply in order",
+			scores: []WeekScore{
+				{UserID: 1, Points: 6, CorrectPicks: 6, SpreadWins: 2, TotalPicks: 5},
+				{UserID: 2, Points: 6, CorrectPicks: 5, SpreadWins: 4, TotalPicks: 5},
+				{UserID: 3, Points: 6, CorrectPicks: 6, SpreadWins: 3, TotalPicks: 5},
+			},
+			tiebreakers: []string{models.TiebreakerCorrectPicks, models.TiebreakerSpreadWins},
+			want:        []uint{3},
+		},
+		{
+			name: "tie that survives every tiebreaker is shared",
+			scores: []WeekScore{
+				{UserID: 1, Points: 6, CorrectPicks: 6, TotalPicks: 5},
+				{UserID: 2, Points: 6, CorrectPicks: 6, TotalPicks: 5},
+			},
+			tiebreakers: []string{models.TiebreakerCorrectPicks},
+			want:        []uint{1, 2},
+		},
+		{
+			name: "closest guess without going over",
+			scores: []WeekScore{
+				{UserID: 1, Points: 6, TotalPicks: 5, GuessMiss: intPtr(1), GuessOver: true},
+				{UserID: 2, Points: 6, TotalPicks: 5, GuessMiss: intPtr(4)},
+				{UserID: 3, Points: 6, TotalPicks: 5},
+			},
+			tiebreakers: []string{models.TiebreakerTotalGuess},
+			want:        []uint{2},
+		},
+		{
+			name: "closest guess by absolute difference",
+			scores: []WeekScore{
+				{UserID: 1, Points: 6, TotalPicks: 5, GuessMiss: intPtr(1)},
+				{UserID: 2, Points: 6, TotalPicks: 5, GuessMiss: intPtr(4)},
+			},
+			tiebreakers: []string{models.TiebreakerTotalGuess},
+			want:        []uint{1},
+		},
+		{
+			name:   "users without picks can't win",
+			scores: []WeekScore{{UserID: 1, TotalPicks: 0}},
+			want:   []uint{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, WeekWinners(tt.scores, tt.tiebreakers))
+		})
+	}
+}
+
+func TestWeeklyGrid(t *testing.T) {
+	db := setupTestDB(t)
+	leagueID := seedTestData(t, db)
+
+	var season models.Season
+	db.Where("year = ?", 2024).First(&season)
+
+	// Bob misses week 2, Alice and Charlie tie on points and Charlie wins on spread picks
+	week2 := models.Week{SeasonID: season.ID, WeekNumber: 2, Name: "Week 2", Status: "scoring"}
+	db.Create(&week2)
+	game := models.Game{WeekID: week2.ID, HomeTeamID: 1, AwayTeamID: 2, IsFinal: true}
+	db.Create(&game)
+	var alice, charlie models.User
+	db.Where("username = ?", "alice").First(&alice)
+	db.Where("username = ?", "charlie").First(&charlie)
+	db.Create(&models.Pick{LeagueID: leagueID, UserID: alice.ID, GameID: game.ID, PickedTeamID: 1, PickedOverUnder: "over",
+		SpreadOutcome: models.PickOutcomeLoss, OverUnderOutcome: models.PickOutcomeWin, PointsEarned: 1})
+	db.Create(&models.Pick{LeagueID: leagueID, UserID: charlie.ID, GameID: game.ID, PickedTeamID: 2, PickedOverUnder: "under",
+		SpreadOutcome: models.PickOutcomeWin, OverUnderOutcome: models.PickOutcomeLoss, PointsEarned: 1})
+
+	// Only Alice has picked week 3, whose game hasn't been played
+	week3 := models.Week{SeasonID: season.ID, WeekNumber: 3, Name: "Week 3", Status: "picking"}
+	db.Create(&week3)
+	upcoming := models.Game{WeekID: week3.ID, HomeTeamID: 1, AwayTeamID: 2}
+	db.Create(&upcoming)
+	db.Create(&models.Pick{LeagueID: leagueID, UserID: alice.ID, GameID: upcoming.ID, PickedTeamID: 1, PickedOverUnder: "over"})
+
+	grid, err := WeeklyGrid(db, leagueID, season.ID, []string{models.TiebreakerCorrectPicks, models.TiebreakerSpreadWins}, models.GuessWithoutGoingOver)
+
+	assert.NoError(t, err)
+	if !assert.Len(t, grid.Weeks, 3) || !assert.Len(t, grid.Rows, 3) {
+		return
+	}
+
+	// Week 1: Alice 2 points beats Bob's 1
+	assert.True(t, grid.Weeks[0].Final)
+	assert.Equal(t, []uint{alice.ID}, grid.Weeks[0].Winners)
+	assert.False(t, grid.Weeks[1].Final)
+	assert.Equal(t, []uint{charlie.ID}, grid.Weeks[1].Winners)
+
+	// Nobody wins a week before any of its games is final
+	assert.Empty(t, grid.Weeks[2].Winners)
+
+	// Rows line up with weeks, and members without picks get empty cells
+	assert.Equal(t, "alice", grid.Rows[0].Username)
+	assert.Equal(t, 3, grid.Rows[0].TotalPoints)
+	assert.Equal(t, []int{2, 1}, []int{grid.Rows[0].Weeks[0].Points, grid.Rows[0].Weeks[1].Points})
+	assert.Equal(t, "bob", grid.Rows[1].Username)
+	assert.Equal(t, 0, grid.Rows[1].Weeks[1].TotalPicks)
+	assert.Equal(t, week2.ID, grid.Rows[1].Weeks[1].WeekID)
+	assert.Equal(t, "charlie", grid.Rows[2].Username)
+	assert.Equal(t, 0, grid.Rows[2].Weeks[0].Points)
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestWeeklyGrid_TotalGuess(t *testing.T) {
+	db := setupTestDB(t)
+	leagueID := seedTestData(t, db)
+
+	var season models.Season
+	db.Where("year = ?", 2024).First(&season)
+	var week models.Week
+	db.Where("season_id = ?", season.ID).First(&week)
+	var alice, bob models.User
+	db.Where("username = ?", "alice").First(&alice)
+	db.Where("username = ?", "bob").First(&bob)
+
+	// The tiebreaker game finished 31-21; Alice went over by 2 and Bob was under by 7
+	home, away := 31, 21
+	game := models.Game{WeekID: week.ID, HomeTeamID: 1, AwayTeamID: 2, IsFinal: true, HomeScore: &home, AwayScore: &away}
+	db.Create(&game)
+	db.Model(&week).Update("tiebreaker_game_id", game.ID)
+	db.Create(&models.TiebreakerGuess{LeagueID: leagueID, UserID: alice.ID, WeekID: week.ID, TotalPoints: 54})
+	db.Create(&models.TiebreakerGuess{LeagueID: leagueID, UserID: bob.ID, WeekID: week.ID, TotalPoints: 45})
+
+	tests := []struct {
+		rule     string
+		wantOver bool
+	}{
+		{models.GuessWithoutGoingOver, true},
+		{models.GuessClosest, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			grid, err := WeeklyGrid(db, leagueID, season.ID, []string{models.TiebreakerTotalGuess}, tt.rule)
+			assert.NoError(t, err)
+			if !assert.Len(t, grid.Rows, 3) || !assert.Len(t, grid.Rows[0].Weeks, 1) {
+				return
+			}
+
+			score := grid.Rows[0].Weeks[0]
+			if assert.NotNil(t, score.TotalGuess) && assert.NotNil(t, score.GuessMiss) {
+				assert.Equal(t, 54, *score.TotalGuess)
+				assert.Equal(t, 2, *score.GuessMiss)
+			}
+			assert.Equal(t, tt.wantOver, score.GuessOver)
+			assert.Nil(t, grid.Rows[2].Weeks[0].GuessMiss)
+		})
+	}
+}
@@ -23,6 +23,102 @@ type League struct {
 	Owner       User                 `gorm:"foreignKey:OwnerID" json:"owner,omitempty"`
 	Members     []LeagueMembership   `gorm:"foreignKey:LeagueID;constraint:OnDelete:CASCADE" json:"members,omitempty"`
 	Seasons     []Season             `gorm:"foreignKey:LeagueID;constraint:OnDelete:CASCADE" json:"seasons,omitempty"`
+	Settings    *LeagueSettings      `gorm:"foreignKey:LeagueID;constraint:OnDelete:CASCADE" json:"settings,omitempty"`
+}
+
+// League formats
+const (
+	LeagueFormatStandard   = "standard"   // Points per correct spread and over/under pick
+	LeagueFormatConfidence = "confidence" // Users rank each week's games 1..N; a correct pick earns its confidence value
+	LeagueFormatSurvivor   = "survivor"   // Users pick one team per week, can't reuse a team, and are out after a loss
+)
+
+// LeagueSettings holds commissioner-configurable rules for a league
+// Leagues without a row use the default scoring (see scoring.DefaultSettings)
+type LeagueSettings struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	LeagueID uint   `gorm:"not null;uniqueIndex" json:"league_id"`
+	Format   string `gorm:"default:'standard'" json:"format"` // standard, confidence, survivor
+
+	// Scoring (no gorm defaults so that zero values can be saved)
+	SpreadPoints     int  `gorm:"not null" json:"spread_points"`      // Points for covering the spread
+	OverUnderPoints  int  `gorm:"not null" json:"over_under_points"`  // Points for a correct over/under
+	PushPoints       int  `gorm:"not null" json:"push_points"`        // Points for a pick that pushes
+	UpsetBonusPoints int  `gorm:"not null" json:"upset_bonus_points"` // Extra points for picking an underdog that wins outright
+	OverUnderEnabled bool `gorm:"not null" json:"over_under_enabled"` // False = spread-only league
+
+	// Locking
+	LockAtKickoff bool `gorm:"default:false" json:"lock_at_kickoff"` // Each pick locks at its game's kickoff instead of the week's PickDeadline
+
+	// Schedule changes
+	PostponedPicks string `gorm:"default:'keep'" json:"postponed_picks"` // keep or void picks on a game moved to another week
+
+	// Missed picks
+	AutoPick string `gorm:"default:'none'" json:"auto_pick"` // How games a member didn't pick are filled in when the week locks
+
+	// Standings
+	Tiebreakers  string `gorm:"default:'correct_picks,spread_wins'" json:"tiebreakers"` // Comma-separated, applied in order to users tied on points
+	RankingStyle string `gorm:"default:'standard'" json:"ranking_style"`                // standard (1, 2, 2, 4) or dense (1, 2, 2, 3)
+	GuessRule    string `gorm:"default:'without_going_over'" json:"guess_rule"`         // How total-points guesses on the tiebreaker game are compared
+}
+
+// What happens to picks on a postponed game that moves to another week
+const (
+	PostponedPicksKeep = "keep" // Picks move with the game
+	PostponedPicksVoid = "void" // Picks are voided and members pick again
+)
+
+// Auto-pick policies for games a member didn't pick by the time the week locks
+const (
+	AutoPickNone      = "none"      // Missed games score nothing
+	AutoPickFavorite  = "favorite"  // The team favored by the spread
+	AutoPickHome      = "home"      // The home team
+	AutoPickUnderdog  = "underdog"  // The team getting points
+	AutoPickRandom    = "random"    // Either team at random
+	AutoPickConsensus = "consensus" // The sides most of the league picked last week
+)
+
+// Tiebreakers for users tied on points, most points wins a tiebreaker
+const (
+	TiebreakerCorrectPicks = "correct_picks"       // Correct spread and over/under picks
+	TiebreakerSpreadWins   = "spread_wins"         // Correct spread picks
+	TiebreakerHeadToHead   = "head_to_head"        // Tied users outscored on games both picked (season standings only)
+	TiebreakerEarliest     = "earliest_submission" // First pick made earliest (season standings only)
+	TiebreakerTotalGuess   = "total_guess"         // Closest guess at the week's tiebreaker game total
+)
+
+// How total-points guesses on a week's tiebreaker game are compared
+const (
+	GuessWithoutGoingOver = "without_going_over" // Closest without going over; any guess over ranks behind every guess that isn't
+	GuessClosest          = "closest"            // Smallest absolute difference
+)
+
+// How users tied after every tiebreaker are ranked
+const (
+	RankingStandard = "standard" // Competition ranking: 1, 2, 2, 4
+	RankingDense    = "dense"    // Dense ranking: 1, 2, 2, 3
+)
+
+// TiebreakerGuess is a member's guess at the combined score of a week's tiebreaker game
+// One per member per week; it locks with the member's pick on that game
+type TiebreakerGuess struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	LeagueID    uint `gorm:"not null;uniqueIndex:idx_guess_week" json:"league_id"`
+	UserID      uint `gorm:"not null;uniqueIndex:idx_guess_week" json:"user_id"`
+	WeekID      uint `gorm:"not null;uniqueIndex:idx_guess_week" json:"week_id"`
+	TotalPoints int  `gorm:"not null" json:"total_points"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Week Week `gorm:"foreignKey:WeekID" json:"week,omitempty"`
 }
 
 // LeagueMembership represents a user's membership in a league
@@ -91,12 +187,31 @@ type Week struct {
 	Name         string     `json:"name"` // e.g., "Week 8"
 	Status       string     `gorm:"default:'creating'" json:"status"` // creating, picking, scoring, finished
 	PickDeadline *time.Time `json:"pick_deadline"` // When picks must be submitted by (set when transitioning to 'picking')
+	IsPostseason bool       `gorm:"default:false" json:"is_postseason"` // Bowl/playoff week; games are organized into a bracket
+	// Game whose combined score members guess to break ties; set by the commissioner
+	TiebreakerGameID *uint `json:"tiebreaker_game_id,omitempty"`
 
 	// Relationships
 	Season Season `gorm:"foreignKey:SeasonID" json:"season,omitempty"`
 	Games  []Game `gorm:"foreignKey:WeekID" json:"games,omitempty"`
 }
 
+// WeekTransition records a week status change and who or what made it
+type WeekTransition struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	WeekID     uint   `gorm:"not null;index" json:"week_id"`
+	FromStatus string `gorm:"not null" json:"from_status"`
+	ToStatus   string `gorm:"not null" json:"to_status"`
+	Source     string `gorm:"not null" json:"source"` // "user" or "scheduler"
+	UserID     *uint  `json:"user_id"`                // Set when a user made the change
+	Reason     string `json:"reason"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
 // Team represents a college football team
 type Team struct {
 	ID        uint           `gorm:"primaryKey" json:"id"`
@@ -108,6 +223,18 @@ type Team struct {
 	Abbreviation string `gorm:"uniqueIndex" json:"abbreviation"`
 	LogoURL      string `json:"logo_url"`
 	Conference   string `json:"conference"`
+
+	// Relationships
+	Aliases []TeamAlias `gorm:"foreignKey:TeamID;constraint:OnDelete:CASCADE" json:"aliases,omitempty"`
+}
+
+// TeamAlias is another name a team goes by in external feeds (e.g. "Mississippi" for Ole Miss)
+type TeamAlias struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	TeamID uint   `gorm:"not null;index" json:"team_id"`
+	Alias  string `gorm:"uniqueIndex;not null" json:"alias"`
 }
 
 // Game represents a single game
@@ -123,6 +250,10 @@ type Game struct {
 	GameTime    time.Time  `json:"game_time"`
 	HomeSpread  float64    `json:"home_spread"` // Negative means home team favored
 	Total       float64    `json:"total"`       // Over/under line for combined score
+	Status      string     `gorm:"default:'scheduled'" json:"status"` // scheduled, in_progress, final, postponed, cancelled
+	ExternalID  string     `gorm:"index" json:"external_id,omitempty"` // The scores feed's ID for this game, if known
+	// The master schedule game this league game was selected from; its result is entered once there
+	ScheduledGameID *uint `gorm:"index" json:"scheduled_game_id,omitempty"`
 
 	// Game Results (null until game is final)
 	IsFinal      bool  `gorm:"default:false" json:"is_final"`
@@ -130,6 +261,10 @@ type Game struct {
 	AwayScore    *int  `json:"away_score"`
 	WinnerTeamID *uint `json:"winner_team_id"` // null for tie
 
+	// Live state (set while the game is in progress)
+	Period int    `json:"period"` // 1-4, 5 and up for overtime
+	Clock  string `json:"clock"`  // Time left in the period, e.g. "7:42"
+
 	// Relationships
 	Week     Week   `gorm:"foreignKey:WeekID" json:"week,omitempty"`
 	HomeTeam Team   `gorm:"foreignKey:HomeTeamID" json:"home_team,omitempty"`
@@ -137,6 +272,90 @@ type Game struct {
 	Picks    []Pick `gorm:"foreignKey:GameID" json:"picks,omitempty"`
 }
 
+// ScheduledGame is a real-world game on the shared master schedule, managed by global admins
+// Leagues add it to a week's slate as a Game linked back here, and a result entered on it scores every league's picks
+type ScheduledGame struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	Year       int       `gorm:"not null;index:idx_scheduled_game_week" json:"year"`
+	WeekNumber int       `gorm:"not null;index:idx_scheduled_game_week" json:"week_number"`
+	HomeTeamID uint      `gorm:"not null" json:"home_team_id"`
+	AwayTeamID uint      `gorm:"not null" json:"away_team_id"`
+	GameTime   time.Time `json:"game_time"`
+	HomeSpread float64   `json:"home_spread"` // Suggested line, copied to league games when selected
+	Total      float64   `json:"total"`
+	Status     string    `gorm:"default:'scheduled'" json:"status"`
+	ExternalID string    `gorm:"index" json:"external_id,omitempty"`
+
+	// Game Results (null until game is final)
+	IsFinal   bool `gorm:"default:false" json:"is_final"`
+	HomeScore *int `json:"home_score"`
+	AwayScore *int `json:"away_score"`
+
+	// Relationships
+	HomeTeam Team `gorm:"foreignKey:HomeTeamID" json:"home_team,omitempty"`
+	AwayTeam Team `gorm:"foreignKey:AwayTeamID" json:"away_team,omitempty"`
+}
+
+// Game statuses
+const (
+	GameStatusScheduled  = "scheduled"
+	GameStatusInProgress = "in_progress"
+	GameStatusFinal      = "final"
+	GameStatusPostponed  = "postponed" // Not played at its scheduled time; may move to another week
+	GameStatusCancelled  = "cancelled" // Won't be played; picks on it are voided
+)
+
+// GameResult records a score submitted for a game
+// Every submission is kept so that corrections to a final result can be audited
+type GameResult struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	GameID       uint   `gorm:"not null;index" json:"game_id"`
+	HomeScore    int    `json:"home_score"`
+	AwayScore    int    `json:"away_score"`
+	IsFinal      bool   `json:"is_final"`
+	IsCorrection bool   `json:"is_correction"`          // Replaced a result that was already final
+	Source       string `gorm:"not null" json:"source"` // "user" or "scheduler"
+	UserID       *uint  `json:"user_id"`                // Set when a user submitted the score
+	Reason       string `json:"reason"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// GameLine records a game's spread and total each time they're set
+// The history shows when and how a line moved after picks were made
+type GameLine struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	GameID     uint    `gorm:"not null;index" json:"game_id"`
+	HomeSpread float64 `json:"home_spread"`
+	Total      float64 `json:"total"`
+	Source     string  `gorm:"not null" json:"source"` // "user", "scheduler" or "cli"
+	UserID     *uint   `json:"user_id"`                // Set when a user changed the line
+	Reason     string  `json:"reason"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// PickOutcome is the graded result of a spread or over/under pick
+type PickOutcome string
+
+const (
+	PickOutcomePending PickOutcome = ""     // Game is not final yet
+	PickOutcomeWin     PickOutcome = "win"  // Pick was correct
+	PickOutcomeLoss    PickOutcome = "loss" // Pick was incorrect
+	PickOutcomePush    PickOutcome = "push" // Result landed exactly on the line
+	PickOutcomeVoid    PickOutcome = "void" // Pick no longer counts (e.g. game not played)
+)
+
 // Pick represents a user's pick for a game
 type Pick struct {
 	ID        uint           `gorm:"primaryKey" json:"id"`
@@ -149,12 +368,23 @@ type Pick struct {
 	GameID          uint   `gorm:"not null;uniqueIndex:idx_league_user_game" json:"game_id"` // Updated index
 	PickedTeamID    uint   `gorm:"not null" json:"picked_team_id"`
 	PickedOverUnder string `json:"picked_over_under"` // "over" or "under"
-	Confidence      int    `json:"confidence"`         // Optional: for confidence pools
+	Confidence      int    `json:"confidence"`         // Confidence pools: unique 1..N within a user's week
+
+	// Line the user picked against, snapshotted from the game at submit time so later line edits
+	// don't change how the pick is graded. Nil on picks made before snapshots were kept
+	HomeSpread *float64 `json:"home_spread,omitempty"`
+	Total      *float64 `json:"total,omitempty"`
 
 	// Scoring (one point for spread pick, one point for over/under pick)
-	SpreadCorrect     *bool `json:"spread_correct"`      // null until game is final
-	OverUnderCorrect  *bool `json:"over_under_correct"`  // null until game is final
-	PointsEarned      int   `gorm:"default:0" json:"points_earned"`
+	SpreadOutcome     PickOutcome `gorm:"size:10;index" json:"spread_outcome"`     // empty until game is final
+	OverUnderOutcome  PickOutcome `gorm:"size:10;index" json:"over_under_outcome"` // empty until game is final
+	PointsEarned      int         `gorm:"default:0" json:"points_earned"`
+
+	// Set when the league's commissioner entered or last changed the pick for the member
+	CommissionerEntered bool  `gorm:"default:false" json:"commissioner_entered"`
+	EnteredByID         *uint `json:"entered_by_id,omitempty"`
+	// Filled in by the league's auto-pick policy because the member missed the game
+	AutoPicked bool `gorm:"default:false" json:"auto_picked"`
 
 	// Relationships
 	League      League `gorm:"foreignKey:LeagueID" json:"league,omitempty"`      // NEW
@@ -163,6 +393,115 @@ type Pick struct {
 	PickedTeam  Team   `gorm:"foreignKey:PickedTeamID" json:"picked_team,omitempty"`
 }
 
+// Pick change actions
+const (
+	PickChangeCreate = "create"
+	PickChangeUpdate = "update"
+)
+
+// PickChange records a pick each time it's created or updated
+// Rows are only ever appended, so there's a record of what a member picked and when
+type PickChange struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	PickID   uint   `gorm:"not null;index" json:"pick_id"`
+	LeagueID uint   `gorm:"not null;index:idx_pick_change_member" json:"league_id"`
+	UserID   uint   `gorm:"not null;index:idx_pick_change_member" json:"user_id"` // Whose pick it is
+	GameID   uint   `gorm:"not null;index" json:"game_id"`
+	Action   string `gorm:"not null" json:"action"` // "create" or "update"
+
+	// Values before the change, nil for creates
+	OldPickedTeamID    *uint   `json:"old_picked_team_id"`
+	OldPickedOverUnder *string `json:"old_picked_over_under"`
+	OldConfidence      *int    `json:"old_confidence"`
+
+	NewPickedTeamID    uint   `json:"new_picked_team_id"`
+	NewPickedOverUnder string `json:"new_picked_over_under"`
+	NewConfidence      int    `json:"new_confidence"`
+
+	Source  string `gorm:"not null" json:"source"` // "user", "scheduler" or "cli"
+	ActorID *uint  `json:"actor_id"`               // Set when a user made the change
+	Reason  string `json:"reason"`
+	IP      string `json:"-"` // Address the request came from, empty for background changes; only shown to the commissioner
+	// Made by the commissioner on the member's behalf, which can be after the pick deadline
+	Override bool `gorm:"default:false;index" json:"override"`
+
+	// Relationships
+	User  *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Actor *User `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+}
+
+// SurvivorPick is a survivor-league member's single team pick for a week
+// A team can only be used once per season, and a loss eliminates the member
+type SurvivorPick struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	LeagueID uint `gorm:"not null;uniqueIndex:idx_survivor_week;uniqueIndex:idx_survivor_team" json:"league_id"`
+	UserID   uint `gorm:"not null;uniqueIndex:idx_survivor_week;uniqueIndex:idx_survivor_team" json:"user_id"`
+	SeasonID uint `gorm:"not null;uniqueIndex:idx_survivor_team" json:"season_id"` // Denormalized to enforce one use per team per season
+	WeekID   uint `gorm:"not null;uniqueIndex:idx_survivor_week" json:"week_id"`    // One pick per week
+	GameID   uint `gorm:"not null;index" json:"game_id"`
+	TeamID   uint `gorm:"not null;uniqueIndex:idx_survivor_team" json:"team_id"`
+
+	Outcome PickOutcome `gorm:"size:10" json:"outcome"` // Straight-up result: win, or loss (ties eliminate too)
+
+	// Relationships
+	League League `gorm:"foreignKey:LeagueID" json:"league,omitempty"`
+	User   User   `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Week   Week   `gorm:"foreignKey:WeekID" json:"week,omitempty"`
+	Game   Game   `gorm:"foreignKey:GameID" json:"game,omitempty"`
+	Team   Team   `gorm:"foreignKey:TeamID" json:"team,omitempty"`
+}
+
+// BracketSlot is one matchup in a postseason bracket
+// First-round slots are created with known teams; later rounds are fed by the winners of earlier slots
+type BracketSlot struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	WeekID   uint      `gorm:"not null;index" json:"week_id"`
+	Round    int       `gorm:"not null" json:"round"`    // 1 = first round; scoring escalates each round
+	Position int       `json:"position"`                 // Order within the round
+	Name     string    `json:"name"`                     // e.g., "Rose Bowl (CFP Quarterfinal)"
+	GameTime time.Time `json:"game_time"`                // Kickoff used when the game is created
+
+	HomeSourceSlotID *uint `json:"home_source_slot_id"` // Winner of this slot becomes the home team
+	AwaySourceSlotID *uint `json:"away_source_slot_id"` // Winner of this slot becomes the away team
+	GameID           *uint `gorm:"uniqueIndex" json:"game_id"` // null until both teams are known
+
+	// Relationships
+	Week Week  `gorm:"foreignKey:WeekID" json:"week,omitempty"`
+	Game *Game `gorm:"foreignKey:GameID" json:"game,omitempty"`
+}
+
+// BracketPick is a user's predicted winner for one bracket slot
+// Users fill out every slot before the first postseason game
+type BracketPick struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	LeagueID     uint `gorm:"not null;uniqueIndex:idx_bracket_pick" json:"league_id"`
+	UserID       uint `gorm:"not null;uniqueIndex:idx_bracket_pick" json:"user_id"`
+	SlotID       uint `gorm:"not null;uniqueIndex:idx_bracket_pick" json:"slot_id"`
+	PickedTeamID uint `gorm:"not null" json:"picked_team_id"`
+
+	Outcome      PickOutcome `gorm:"size:10" json:"outcome"` // empty until the slot's game is final
+	PointsEarned int         `gorm:"default:0" json:"points_earned"`
+
+	// Relationships
+	User       User        `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Slot       BracketSlot `gorm:"foreignKey:SlotID" json:"slot,omitempty"`
+	PickedTeam Team        `gorm:"foreignKey:PickedTeamID" json:"picked_team,omitempty"`
+}
+
 // Leaderboard is a view/calculated model for displaying standings
 type LeaderboardEntry struct {
 	LeagueID     uint    `json:"league_id"`      // NEW: Which league this leaderboard is for
@@ -171,6 +510,37 @@ type LeaderboardEntry struct {
 	DisplayName  string  `json:"display_name"`
 	TotalPoints  int     `json:"total_points"`
 	CorrectPicks int     `json:"correct_picks"`
+	PushPicks    int     `json:"push_picks"`
 	TotalPicks   int     `json:"total_picks"`
 	WinPct       float64 `json:"win_pct"`
+
+	// Standing, with the values ties were broken on
+	Rank           int        `json:"rank"` // Shared by users still tied after every tiebreaker
+	SpreadWins     int        `json:"spread_wins"`
+	HeadToHeadWins int        `json:"head_to_head_wins"` // Users tied on points this user outscored on games they both picked
+	FirstPickAt    *time.Time `json:"first_pick_at,omitempty"`
+	// Guess on the tiebreaker game of the latest week it's final for; GuessOver is only set when going over loses
+	TotalGuess *int `json:"total_guess,omitempty"`
+	GuessMiss  *int `json:"guess_miss,omitempty"` // Points away from the actual total
+	GuessOver  bool `json:"guess_over,omitempty"`
+}
+
+// Standing is a user's pick totals for one week of a league, kept up to date as picks are made and graded
+// Leaderboards sum these rows instead of every pick. Rows are derived from picks, so they're replaced rather
+// than edited and can be rebuilt with cmd/rebuild-standings
+type Standing struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	LeagueID uint `gorm:"not null;uniqueIndex:idx_standing_week" json:"league_id"`
+	SeasonID uint `gorm:"not null;index" json:"season_id"`
+	WeekID   uint `gorm:"not null;uniqueIndex:idx_standing_week;index" json:"week_id"`
+	UserID   uint `gorm:"not null;uniqueIndex:idx_standing_week" json:"user_id"`
+
+	Points       int `gorm:"not null" json:"points"`
+	CorrectPicks int `gorm:"not null" json:"correct_picks"` // Correct spread and over/under picks
+	PushPicks    int `gorm:"not null" json:"push_picks"`
+	SpreadWins   int `gorm:"not null" json:"spread_wins"`
+	DecidedPicks int `gorm:"not null" json:"decided_picks"` // Spread and over/under picks won or lost, for win percentage
+	TotalPicks   int `gorm:"not null" json:"total_picks"`   // Games picked
 }
@@ -0,0 +1,102 @@
+package odds
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileProvider reads lines from a local JSON or CSV file
+// JSON files hold a list of Line. CSV files need a header row with home_team, away_team, home_spread
+// and total columns, plus an optional kickoff column (RFC3339)
+type FileProvider struct {
+	path string
+}
+
+// NewFileProvider creates a provider for a .json or .csv file
+// The file is re-read on every import
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{path: path}
+}
+
+// Name implements Provider
+func (f *FileProvider) Name() string {
+	return "file " + f.path
+}
+
+// Lines implements Provider
+func (f *FileProvider) Lines(ctx context.Context) ([]Line, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(f.path)) {
+	case ".json":
+		var lines []Line
+		if err := json.Unmarshal(data, &lines); err != nil {
+			return nil, fmt.Errorf("invalid odds file: %w", err)
+		}
+		return lines, nil
+	case ".csv":
+		return parseCSV(string(data))
+	default:
+		return nil, fmt.Errorf("unsupported odds file type %q (expected .json or .csv)", filepath.Ext(f.path))
+	}
+}
+
+// parseCSV reads lines from CSV with a header row
+func parseCSV(data string) ([]Line, error) {
+	records, err := csv.NewReader(strings.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid odds file: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"home_team", "away_team", "home_spread", "total"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("invalid odds file: missing %s column", required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var lines []Line
+	for n, record := range records[1:] {
+		row := n + 2 // 1-based, after the header
+
+		line := Line{HomeTeam: field(record, "home_team"), AwayTeam: field(record, "away_team")}
+		if line.HomeSpread, err = strconv.ParseFloat(field(record, "home_spread"), 64); err != nil {
+			return nil, fmt.Errorf("invalid odds file: row %d: bad home_spread: %w", row, err)
+		}
+		if line.Total, err = strconv.ParseFloat(field(record, "total"), 64); err != nil {
+			return nil, fmt.Errorf("invalid odds file: row %d: bad total: %w", row, err)
+		}
+		if kickoff := field(record, "kickoff"); kickoff != "" {
+			if line.Kickoff, err = time.Parse(time.RFC3339, kickoff); err != nil {
+				return nil, fmt.Errorf("invalid odds file: row %d: bad kickoff: %w", row, err)
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
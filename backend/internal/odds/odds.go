@@ -0,0 +1,181 @@
+package odds
+
+import (
+	"context"
+	"time"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/teammatch"
+	"github.com/ckinger23/mountaintop/internal/weeks"
+	"gorm.io/gorm"
+)
+
+// Line is a sportsbook's current line for one game
+type Line struct {
+	HomeTeam   string    `json:"home_team"` // Team name, abbreviation or alias
+	AwayTeam   string    `json:"away_team"` // Team name, abbreviation or alias
+	Kickoff    time.Time `json:"kickoff"`   // Optional; used to tell apart rematches
+	HomeSpread float64   `json:"home_spread"`
+	Total      float64   `json:"total"`
+}
+
+// Provider reports current lines from a sportsbook feed
+type Provider interface {
+	// Name identifies the provider in logs and reports
+	Name() string
+	// Lines returns every line the feed currently offers
+	Lines(ctx context.Context) ([]Line, error)
+}
+
+// Change is a game whose line differs from the feed's
+type Change struct {
+	GameID        uint    `json:"game_id"`
+	HomeTeam      string  `json:"home_team"`
+	AwayTeam      string  `json:"away_team"`
+	OldHomeSpread float64 `json:"old_home_spread"`
+	NewHomeSpread float64 `json:"new_home_spread"`
+	OldTotal      float64 `json:"old_total"`
+	NewTotal      float64 `json:"new_total"`
+}
+
+// UnmatchedGame is a game in the week with no line in the feed
+type UnmatchedGame struct {
+	GameID   uint   `json:"game_id"`
+	HomeTeam string `json:"home_team"`
+	AwayTeam string `json:"away_team"`
+}
+
+// UnmatchedLine is a feed line that couldn't be tied to a game in the week
+type UnmatchedLine struct {
+	Line   Line   `json:"line"`
+	Reason string `json:"reason"`
+}
+
+// Report compares a week's games against a feed
+type Report struct {
+	Provider       string          `json:"provider"`
+	Changed        []Change        `json:"changed"`
+	Unchanged      int             `json:"unchanged"`
+	UnmatchedGames []UnmatchedGame `json:"unmatched_games"`
+	UnmatchedLines []UnmatchedLine `json:"unmatched_lines"`
+}
+
+// Compare matches feed lines to games and reports which lines would change
+// A line whose teams are listed the other way round still matches, with its spread flipped to our home team.
+// games must have their HomeTeam and AwayTeam loaded. Final and cancelled games are left out
+func Compare(games []models.Game, lines []Line, resolver *teammatch.Resolver) Report {
+	report := Report{
+		Changed:        []Change{},
+		UnmatchedGames: []UnmatchedGame{},
+		UnmatchedLines: []UnmatchedLine{},
+	}
+	matched := make(map[uint]bool)
+
+	for _, line := range lines {
+		home, ok := resolver.Resolve(line.HomeTeam)
+		if !ok {
+			report.UnmatchedLines = append(report.UnmatchedLines, UnmatchedLine{Line: line, Reason: "unknown team: " + line.HomeTeam})
+			continue
+		}
+		away, ok := resolver.Resolve(line.AwayTeam)
+		if !ok {
+			report.UnmatchedLines = append(report.UnmatchedLines, UnmatchedLine{Line: line, Reason: "unknown team: " + line.AwayTeam})
+			continue
+		}
+
+		game, flipped := findGame(games, home.ID, away.ID, line.Kickoff)
+		if game == nil {
+			report.UnmatchedLines = append(report.UnmatchedLines, UnmatchedLine{Line: line, Reason: "no open game between these teams this week"})
+			continue
+		}
+		if matched[game.ID] {
+			report.UnmatchedLines = append(report.UnmatchedLines, UnmatchedLine{Line: line, Reason: "duplicate line for this game"})
+			continue
+		}
+		matched[game.ID] = true
+
+		spread := line.HomeSpread
+		if flipped {
+			spread = -spread
+		}
+		if spread == game.HomeSpread && line.Total == game.Total {
+			report.Unchanged++
+			continue
+		}
+		report.Changed = append(report.Changed, Change{
+			GameID:        game.ID,
+			HomeTeam:      game.HomeTeam.Name,
+			AwayTeam:      game.AwayTeam.Name,
+			OldHomeSpread: game.HomeSpread,
+			NewHomeSpread: spread,
+			OldTotal:      game.Total,
+			NewTotal:      line.Total,
+		})
+	}
+
+	for _, game := range games {
+		if !matched[game.ID] && open(game) {
+			report.UnmatchedGames = append(report.UnmatchedGames, UnmatchedGame{GameID: game.ID, HomeTeam: game.HomeTeam.Name, AwayTeam: game.AwayTeam.Name})
+		}
+	}
+
+	return report
+}
+
+// Apply saves the new lines from a report's changes and adds them to each game's line history
+// This function should be called within a transaction
+func Apply(tx *gorm.DB, report Report, actor weeks.Actor) error {
+	for _, change := range report.Changed {
+		if err := tx.Model(&models.Game{}).Where("id = ?", change.GameID).Updates(map[string]interface{}{
+			"home_spread": change.NewHomeSpread,
+			"total":       change.NewTotal,
+		}).Error; err != nil {
+			return err
+		}
+		if err := RecordLine(tx, change.GameID, change.NewHomeSpread, change.NewTotal, actor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordLine adds a line to a game's line history
+// Call it whenever a game's spread or total is set. This function should be called within a transaction
+func RecordLine(tx *gorm.DB, gameID uint, homeSpread, total float64, actor weeks.Actor) error {
+	return tx.Create(&models.GameLine{
+		GameID:     gameID,
+		HomeSpread: homeSpread,
+		Total:      total,
+		Source:     actor.Source,
+		UserID:     actor.UserID,
+		Reason:     actor.Reason,
+	}).Error
+}
+
+// findGame returns the open game between two teams, and whether the feed listed them the other way round
+// When the feed gives a kickoff, games more than a day away from it are skipped
+func findGame(games []models.Game, homeID, awayID uint, kickoff time.Time) (*models.Game, bool) {
+	for i := range games {
+		game := &games[i]
+		if !open(*game) {
+			continue
+		}
+		if !kickoff.IsZero() {
+			if gap := kickoff.Sub(game.GameTime); gap > 24*time.Hour || gap < -24*time.Hour {
+				continue
+			}
+		}
+		if game.HomeTeamID == homeID && game.AwayTeamID == awayID {
+			return game, false
+		}
+		if game.HomeTeamID == awayID && game.AwayTeamID == homeID {
+			return game, true
+		}
+	}
+	return nil, false
+}
+
+// open reports whether a game's line can still be updated
+func open(game models.Game) bool {
+	return !game.IsFinal && game.Status != models.GameStatusCancelled
+}
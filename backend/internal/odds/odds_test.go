@@ -0,0 +1,175 @@
+package odds
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/teammatch"
+	"github.com/ckinger23/mountaintop/internal/weeks"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupTestDB creates an in-memory SQLite database for testing
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+
+	err = db.AutoMigrate(&models.Team{}, &models.TeamAlias{}, &models.Week{}, &models.Game{}, &models.GameLine{})
+	if err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	return db
+}
+
+func TestCompareAndApply(t *testing.T) {
+	db := setupTestDB(t)
+	kickoff := time.Date(2025, 9, 6, 19, 0, 0, 0, time.UTC)
+
+	teams := []models.Team{
+		{Name: "Alabama", Abbreviation: "ALA"},
+		{Name: "Auburn", Abbreviation: "AUB"},
+		{Name: "Ole Miss", Abbreviation: "MISS", Aliases: []models.TeamAlias{{Alias: "Mississippi"}}},
+		{Name: "LSU", Abbreviation: "LSU"},
+		{Name: "Georgia", Abbreviation: "UGA"},
+		{Name: "Florida", Abbreviation: "FLA"},
+	}
+	db.Create(&teams)
+
+	week := models.Week{SeasonID: 1, WeekNumber: 1, Name: "Week 1", Status: "picking"}
+	db.Create(&week)
+
+	games := []models.Game{
+		{WeekID: week.ID, HomeTeamID: teams[0].ID, AwayTeamID: teams[1].ID, GameTime: kickoff, HomeSpread: -7, Total: 50.5},
+		{WeekID: week.ID, HomeTeamID: teams[2].ID, AwayTeamID: teams[3].ID, GameTime: kickoff, HomeSpread: 3, Total: 60},
+		{WeekID: week.ID, HomeTeamID: teams[4].ID, AwayTeamID: teams[5].ID, GameTime: kickoff, HomeSpread: -14, Total: 45},
+	}
+	db.Create(&games)
+	db.Preload("HomeTeam").Preload("AwayTeam").Order("id").Find(&games)
+
+	lines := []Line{
+		{HomeTeam: "alabama", AwayTeam: "AUB", HomeSpread: -7, Total: 50.5},              // unchanged
+		{HomeTeam: "LSU", AwayTeam: "Mississippi", HomeSpread: 2.5, Total: 58.5},         // listed the other way round
+		{HomeTeam: "Vanderbilt", AwayTeam: "Tennessee", HomeSpread: 21, Total: 52},       // unknown team
+		{HomeTeam: "ALA", AwayTeam: "AUB", Kickoff: kickoff.AddDate(0, 0, 7), Total: 51}, // different week
+	}
+
+	resolver, err := teammatch.NewResolver(db)
+	assert.NoError(t, err)
+
+	report := Compare(games, lines, resolver)
+	assert.Equal(t, 1, report.Unchanged)
+	if assert.Len(t, report.Changed, 1) {
+		change := report.Changed[0]
+		assert.Equal(t, games[1].ID, change.GameID)
+		assert.Equal(t, 3.0, change.OldHomeSpread)
+		assert.Equal(t, -2.5, change.NewHomeSpread)
+		assert.Equal(t, 58.5, change.NewTotal)
+	}
+	assert.Equal(t, []UnmatchedGame{{GameID: games[2].ID, HomeTeam: "Georgia", AwayTeam: "Florida"}}, report.UnmatchedGames)
+	if assert.Len(t, report.UnmatchedLines, 2) {
+		assert.Equal(t, "unknown team: Vanderbilt", report.UnmatchedLines[0].Reason)
+		assert.Equal(t, "no open game between these teams this week", report.UnmatchedLines[1].Reason)
+	}
+
+	assert.NoError(t, Apply(db, report, weeks.SchedulerActor("test feed")))
+
+	var updated models.Game
+	db.First(&updated, games[1].ID)
+	assert.Equal(t, -2.5, updated.HomeSpread)
+	assert.Equal(t, 58.5, updated.Total)
+	var unchanged models.Game
+	db.First(&unchanged, games[0].ID)
+	assert.Equal(t, -7.0, unchanged.HomeSpread)
+
+	// Only the changed line is added to the history
+	var history []models.GameLine
+	db.Find(&history)
+	if assert.Len(t, history, 1) {
+		assert.Equal(t, games[1].ID, history[0].GameID)
+		assert.Equal(t, -2.5, history[0].HomeSpread)
+		assert.Equal(t, "test feed", history[0].Reason)
+	}
+}
+
+func TestCompare_SkipsClosedGames(t *testing.T) {
+	teams := []models.Team{{ID: 1, Name: "Alabama", Abbreviation: "ALA"}, {ID: 2, Name: "Auburn", Abbreviation: "AUB"}}
+	games := []models.Game{
+		{ID: 1, HomeTeamID: 1, AwayTeamID: 2, HomeTeam: teams[0], AwayTeam: teams[1], IsFinal: true},
+		{ID: 2, HomeTeamID: 1, AwayTeamID: 2, HomeTeam: teams[0], AwayTeam: teams[1], Status: models.GameStatusCancelled},
+	}
+
+	report := Compare(games, []Line{{HomeTeam: "ALA", AwayTeam: "AUB", HomeSpread: -3, Total: 40}}, teammatch.NewResolverForTeams(teams))
+	assert.Empty(t, report.Changed)
+	assert.Empty(t, report.UnmatchedGames)
+	assert.Len(t, report.UnmatchedLines, 1)
+}
+
+func TestFileProvider(t *testing.T) {
+	kickoff := time.Date(2025, 9, 6, 19, 0, 0, 0, time.UTC)
+	want := []Line{
+		{HomeTeam: "ALA", AwayTeam: "AUB", Kickoff: kickoff, HomeSpread: -7, Total: 50.5},
+		{HomeTeam: "Ole Miss", AwayTeam: "LSU", HomeSpread: 3, Total: 60},
+	}
+
+	tests := []struct {
+		name     string
+		file     string
+		contents string
+		wantErr  bool
+	}{
+		{
+			name:     "json",
+			file:     "lines.json",
+			contents: `[{"home_team": "ALA", "away_team": "AUB", "kickoff": "2025-09-06T19:00:00Z", "home_spread": -7, "total": 50.5}, {"home_team": "Ole Miss", "away_team": "LSU", "home_spread": 3, "total": 60}]`,
+		},
+		{
+			name:     "csv",
+			file:     "lines.csv",
+			contents: "home_team,away_team,kickoff,home_spread,total\nALA,AUB,2025-09-06T19:00:00Z,-7,50.5\nOle Miss,LSU,,3,60\n",
+		},
+		{
+			name:     "csv missing column",
+			file:     "lines.csv",
+			contents: "home_team,away_team,home_spread\nALA,AUB,-7\n",
+			wantErr:  true,
+		},
+		{
+			name:     "csv bad number",
+			file:     "lines.csv",
+			contents: "home_team,away_team,home_spread,total\nALA,AUB,PK,50\n",
+			wantErr:  true,
+		},
+		{
+			name:     "unsupported type",
+			file:     "lines.txt",
+			contents: "ALA -7",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.contents), 0o644); err != nil {
+				t.Fatalf("Failed to write odds file: %v", err)
+			}
+
+			lines, err := NewFileProvider(path).Lines(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, want, lines)
+		})
+	}
+}
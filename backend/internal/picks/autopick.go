@@ -0,0 +1,204 @@
+package picks
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/ckinger23/mountaintop/internal/leaderboard"
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/scoring"
+	"github.com/ckinger23/mountaintop/internal/weeks"
+	"gorm.io/gorm"
+)
+
+// AutoPick fills in every game a league member didn't pick in a week, using the league's auto-pick policy
+// Runs when the week locks so missed games are scored like any other pick. The picks are flagged as auto-picked
+// and a pick on a game that's already final is graded straight away. Over/under auto-picks take the over,
+// except under the random and consensus policies. Consensus copies last week's: the sides (favorite or underdog,
+// over or under) most of the league's picks took in the season's previous week. Survivor leagues are skipped.
+// week must have its Season loaded.
+// This function should be called within a transaction
+func AutoPick(tx *gorm.DB, week *models.Week, actor weeks.Actor) ([]models.Pick, error) {
+	leagueID := week.Season.LeagueID
+	settings, err := scoring.LoadSettings(tx, leagueID)
+	if err != nil {
+		return nil, err
+	}
+	if settings.AutoPick == "" || settings.AutoPick == models.AutoPickNone || settings.Format == models.LeagueFormatSurvivor {
+		return nil, nil
+	}
+
+	var weekGames []models.Game
+	if err := tx.Where("week_id = ?", week.ID).Order("game_time, id").Find(&weekGames).Error; err != nil {
+		return nil, err
+	}
+	games := make([]models.Game, 0, len(weekGames))
+	for _, game := range weekGames {
+		// Games that won't be played this week aren't picked
+		if game.Status != models.GameStatusPostponed && game.Status != models.GameStatusCancelled {
+			games = append(games, game)
+		}
+	}
+
+	var members []models.LeagueMembership
+	if err := tx.Where("league_id = ?", leagueID).Order("user_id").Find(&members).Error; err != nil {
+		return nil, err
+	}
+
+	var existing []models.Pick
+	if err := tx.Joins("JOIN games ON games.id = picks.game_id").
+		Where("picks.league_id = ? AND games.week_id = ?", leagueID, week.ID).
+		Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	picked := make(map[uint]map[uint]bool)        // user -> game
+	usedConfidence := make(map[uint]map[int]bool) // user -> value
+	for _, pick := range existing {
+		if picked[pick.UserID] == nil {
+			picked[pick.UserID] = make(map[uint]bool)
+			usedConfidence[pick.UserID] = make(map[int]bool)
+		}
+		picked[pick.UserID][pick.GameID] = true
+		usedConfidence[pick.UserID][pick.Confidence] = true
+	}
+
+	var lean consensus
+	if settings.AutoPick == models.AutoPickConsensus {
+		if lean, err = lastWeekConsensus(tx, leagueID, week); err != nil {
+			return nil, err
+		}
+	}
+
+	audit := Audit{Actor: actor, Auto: true}
+	audit.Actor.Reason = fmt.Sprintf("auto-pick: %s", settings.AutoPick)
+
+	var autoPicks []models.Pick
+	for _, member := range members {
+		nextConfidence := 1
+		for i := range games {
+			game := &games[i]
+			if picked[member.UserID][game.ID] {
+				continue
+			}
+
+			entry := chooseEntry(settings.AutoPick, game, lean)
+			if settings.Format == models.LeagueFormatConfidence {
+				// Missed games get the lowest values the member hasn't used
+				for usedConfidence[member.UserID][nextConfidence] {
+					nextConfidence++
+				}
+				entry.Confidence = nextConfidence
+				nextConfidence++
+			}
+
+			pick, _, err := Save(tx, leagueID, member.UserID, game, entry, audit)
+			if err != nil {
+				return nil, err
+			}
+			if err := grade(tx, leagueID, game, pick); err != nil {
+				return nil, err
+			}
+			autoPicks = append(autoPicks, *pick)
+		}
+	}
+
+	if len(autoPicks) > 0 {
+		if err := leaderboard.RefreshMembers(tx, leagueID, week.ID); err != nil {
+			return nil, err
+		}
+	}
+	return autoPicks, nil
+}
+
+// consensus is which sides a league's picks took in a week
+type consensus struct {
+	favorites int
+	underdogs int
+	over      int
+	under     int
+}
+
+// lastWeekConsensus tallies the league's picks in the season's week before week
+// Auto-picks aren't counted, so a policy can't feed on itself. A first week has no consensus
+func lastWeekConsensus(tx *gorm.DB, leagueID uint, week *models.Week) (consensus, error) {
+	var tally consensus
+
+	var previous models.Week
+	err := tx.Where("season_id = ? AND week_number < ?", week.SeasonID, week.WeekNumber).
+		Order("week_number DESC").First(&previous).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return tally, nil
+	}
+	if err != nil {
+		return tally, err
+	}
+
+	var lastPicks []models.Pick
+	if err := tx.Preload("Game").
+		Joins("JOIN games ON games.id = picks.game_id").
+		Where("picks.league_id = ? AND games.week_id = ? AND picks.auto_picked = ?", leagueID, previous.ID, false).
+		Find(&lastPicks).Error; err != nil {
+		return tally, err
+	}
+
+	for _, pick := range lastPicks {
+		// The line the pick was made against decides who was favored
+		spread := pick.Game.HomeSpread
+		if pick.HomeSpread != nil {
+			spread = *pick.HomeSpread
+		}
+		favorite, _ := sides(&pick.Game, spread)
+		if pick.PickedTeamID == favorite {
+			tally.favorites++
+		} else {
+			tally.underdogs++
+		}
+
+		switch pick.PickedOverUnder {
+		case "over":
+			tally.over++
+		case "under":
+			tally.under++
+		}
+	}
+	return tally, nil
+}
+
+// sides returns the favorite and underdog on a game at a spread
+// Negative spreads favor the home team; a pick'em counts the home team as the favorite
+func sides(game *models.Game, homeSpread float64) (favorite, underdog uint) {
+	if homeSpread > 0 {
+		return game.AwayTeamID, game.HomeTeamID
+	}
+	return game.HomeTeamID, game.AwayTeamID
+}
+
+// chooseEntry picks a team and over/under on a game for a policy
+// Consensus falls back to the favorite and the over when last week was split or nobody picked
+func chooseEntry(policy string, game *models.Game, tally consensus) Entry {
+	favorite, underdog := sides(game, game.HomeSpread)
+
+	entry := Entry{GameID: game.ID, PickedTeamID: favorite, PickedOverUnder: "over"}
+	switch policy {
+	case models.AutoPickHome:
+		entry.PickedTeamID = game.HomeTeamID
+	case models.AutoPickUnderdog:
+		entry.PickedTeamID = underdog
+	case models.AutoPickRandom:
+		if rand.Intn(2) == 1 {
+			entry.PickedTeamID = underdog
+		}
+		if rand.Intn(2) == 1 {
+			entry.PickedOverUnder = "under"
+		}
+	case models.AutoPickConsensus:
+		if tally.underdogs > tally.favorites {
+			entry.PickedTeamID = underdog
+		}
+		if tally.under > tally.over {
+			entry.PickedOverUnder = "under"
+		}
+	}
+	return entry
+}
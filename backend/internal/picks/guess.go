@@ -0,0 +1,50 @@
+package picks
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"gorm.io/gorm"
+)
+
+// CheckGuess validates a total-points guess on a week's tiebreaker game
+// The guess locks with picks on the tiebreaker game. Problems are reported against the tiebreaker game
+func CheckGuess(db *gorm.DB, week *models.Week, total int, settings models.LeagueSettings, now time.Time) (*Problem, error) {
+	if week.TiebreakerGameID == nil {
+		return &Problem{Code: CodeNoTiebreakerGame, Message: "This week doesn't have a tiebreaker game"}, nil
+	}
+
+	var game models.Game
+	if err := db.First(&game, *week.TiebreakerGameID).Error; err != nil {
+		return nil, err
+	}
+	game.Week = *week
+
+	if week.Status != "picking" {
+		return &Problem{GameID: game.ID, Code: CodePicksNotOpen, Message: "Picks are not open for this week"}, nil
+	}
+	if total < 0 {
+		return &Problem{GameID: game.ID, Code: CodeInvalidGuess, Message: "Total points guess cannot be negative"}, nil
+	}
+	return checkLock(&game, settings, now), nil
+}
+
+// SaveGuess creates or updates a user's total-points guess for a week
+// Call CheckGuess first
+func SaveGuess(db *gorm.DB, leagueID, userID, weekID uint, total int) (*models.TiebreakerGuess, error) {
+	guess := &models.TiebreakerGuess{}
+	err := db.Where("league_id = ? AND user_id = ? AND week_id = ?", leagueID, userID, weekID).First(guess).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	guess.LeagueID = leagueID
+	guess.UserID = userID
+	guess.WeekID = weekID
+	guess.TotalPoints = total
+	if err := db.Save(guess).Error; err != nil {
+		return nil, err
+	}
+	return guess, nil
+}
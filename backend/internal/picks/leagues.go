@@ -0,0 +1,107 @@
+package picks
+
+import (
+	"time"
+
+	"github.com/ckinger23/mountaintop/internal/leaderboard"
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/scoring"
+	"gorm.io/gorm"
+)
+
+// LeagueResult is whether a pick copied to another league was saved there
+type LeagueResult struct {
+	LeagueID uint         `json:"league_id"`
+	GameID   uint         `json:"game_id"`
+	Accepted bool         `json:"accepted"`
+	Pick     *models.Pick `json:"pick,omitempty"`
+	Code     string       `json:"code,omitempty"`
+	Message  string       `json:"error,omitempty"`
+}
+
+// OtherLeagueGames finds the same real-world game in the user's other leagues
+// A game matches if it was selected from the same master schedule game, or has the same teams and kickoff.
+// Returns at most one game per league, with Week.Season loaded
+func OtherLeagueGames(db *gorm.DB, game *models.Game, leagueID, userID uint) ([]models.Game, error) {
+	query := db.Preload("Week.Season").
+		Joins("JOIN weeks ON weeks.id = games.week_id").
+		Joins("JOIN seasons ON seasons.id = weeks.season_id").
+		Joins("JOIN league_memberships ON league_memberships.league_id = seasons.league_id AND league_memberships.user_id = ? AND league_memberships.deleted_at IS NULL", userID).
+		Where("seasons.league_id <> ? AND games.id <> ?", leagueID, game.ID)
+
+	sameTeams := "games.home_team_id = ? AND games.away_team_id = ? AND games.game_time = ?"
+	if game.ScheduledGameID != nil {
+		query = query.Where("(games.scheduled_game_id = ? OR ("+sameTeams+"))", *game.ScheduledGameID, game.HomeTeamID, game.AwayTeamID, game.GameTime)
+	} else {
+		query = query.Where(sameTeams, game.HomeTeamID, game.AwayTeamID, game.GameTime)
+	}
+
+	var found []models.Game
+	if err := query.Order("seasons.league_id, games.id").Find(&found).Error; err != nil {
+		return nil, err
+	}
+
+	games := make([]models.Game, 0, len(found))
+	seen := make(map[uint]bool)
+	for _, g := range found {
+		if seen[g.Week.Season.LeagueID] {
+			continue
+		}
+		seen[g.Week.Season.LeagueID] = true
+		games = append(games, g)
+	}
+	return games, nil
+}
+
+// SaveToLeagues copies a user's pick onto the given games from other leagues
+// Each league checks the pick against its own week status, deadline and settings; a league that rejects it
+// doesn't stop the others. games must have Week.Season loaded, as returned by OtherLeagueGames
+func SaveToLeagues(db *gorm.DB, games []models.Game, userID uint, entry Entry, audit Audit, now time.Time) ([]LeagueResult, error) {
+	results := make([]LeagueResult, 0, len(games))
+	for i := range games {
+		game := &games[i]
+		leagueID := game.Week.Season.LeagueID
+		result := LeagueResult{LeagueID: leagueID, GameID: game.ID}
+
+		settings, err := scoring.LoadSettings(db, leagueID)
+		if err != nil {
+			return nil, err
+		}
+
+		leagueEntry := entry
+		leagueEntry.GameID = game.ID
+
+		problem := Check(game, leagueEntry, settings, now)
+		if problem == nil && settings.Format == models.LeagueFormatConfidence {
+			problems, err := CheckConfidence(db, leagueID, userID, game.WeekID, []Entry{leagueEntry})
+			if err != nil {
+				return nil, err
+			}
+			if len(problems) > 0 {
+				problem = &problems[0]
+			}
+		}
+		if problem != nil {
+			result.Code = problem.Code
+			result.Message = problem.Message
+			results = append(results, result)
+			continue
+		}
+
+		var pick *models.Pick
+		err = db.Transaction(func(tx *gorm.DB) error {
+			var err error
+			if pick, _, err = Save(tx, leagueID, userID, game, leagueEntry, audit); err != nil {
+				return err
+			}
+			return leaderboard.RefreshMembers(tx, leagueID, game.WeekID, userID)
+		})
+		if err != nil {
+			return nil, err
+		}
+		result.Accepted = true
+		result.Pick = pick
+		results = append(results, result)
+	}
+	return results, nil
+}
@@ -0,0 +1,285 @@
+package picks
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/scoring"
+	"github.com/ckinger23/mountaintop/internal/validation"
+	"github.com/ckinger23/mountaintop/internal/weeks"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Problem codes for picks that can't be saved
+const (
+	CodeGameNotFound      = "GAME_NOT_FOUND"
+	CodePicksNotOpen      = "PICKS_NOT_OPEN"
+	CodeGameFinal         = "GAME_FINAL"
+	CodeKickedOff         = "GAME_KICKED_OFF"
+	CodeDeadlinePassed    = "DEADLINE_PASSED"
+	CodeInvalidTeam       = "INVALID_TEAM"
+	CodeInvalidOverUnder  = "INVALID_OVER_UNDER"
+	CodeInvalidConfidence = "INVALID_CONFIDENCE"
+	CodeDuplicateGame     = "DUPLICATE_GAME"
+	CodeGameNotPlayed     = "GAME_NOT_PLAYED"
+	CodeNoTiebreakerGame  = "NO_TIEBREAKER_GAME"
+	CodeInvalidGuess      = "INVALID_GUESS"
+)
+
+// Entry is one pick a user submits for a game
+type Entry struct {
+	GameID          uint   `json:"game_id"`
+	PickedTeamID    uint   `json:"picked_team_id"`
+	PickedOverUnder string `json:"picked_over_under"` // "over" or "under"
+	Confidence      int    `json:"confidence"`
+}
+
+// Problem is why a pick can't be saved
+type Problem struct {
+	GameID  uint   `json:"game_id"`
+	Code    string `json:"code"`
+	Message string `json:"error"`
+}
+
+// Check validates a pick against its game, its week and the league's settings
+// Confidence values are checked separately by CheckConfidence since they depend on the user's other picks.
+// game must have its Week loaded
+func Check(game *models.Game, entry Entry, settings models.LeagueSettings, now time.Time) *Problem {
+	problem := func(code, message string) *Problem {
+		return &Problem{GameID: game.ID, Code: code, Message: message}
+	}
+
+	if game.Week.Status != "picking" {
+		return problem(CodePicksNotOpen, "Picks are not open for this week")
+	}
+	if game.IsFinal {
+		return problem(CodeGameFinal, "Cannot pick a game that is final")
+	}
+	if problem := checkSelection(game, entry); problem != nil {
+		return problem
+	}
+
+	return checkLock(game, settings, now)
+}
+
+// checkLock reports whether picks on a game have locked
+// Picks lock at kickoff or the week's pick deadline, depending on the league
+func checkLock(game *models.Game, settings models.LeagueSettings, now time.Time) *Problem {
+	if weeks.GameLocked(&game.Week, game, settings.LockAtKickoff, now) {
+		if settings.LockAtKickoff {
+			return &Problem{GameID: game.ID, Code: CodeKickedOff, Message: "This game has already kicked off"}
+		}
+		return &Problem{GameID: game.ID, Code: CodeDeadlinePassed, Message: "Pick deadline has passed for this week"}
+	}
+	return nil
+}
+
+// CheckOverride validates a pick a commissioner enters for a member
+// Commissioners aren't held to the week's status, deadline or kickoff, but the game still has to be played
+func CheckOverride(game *models.Game, entry Entry) *Problem {
+	if game.Status == models.GameStatusPostponed || game.Status == models.GameStatusCancelled {
+		return &Problem{GameID: game.ID, Code: CodeGameNotPlayed, Message: "Game has been " + game.Status}
+	}
+	return checkSelection(game, entry)
+}
+
+// checkSelection validates the team and over/under picked for a game
+func checkSelection(game *models.Game, entry Entry) *Problem {
+	if entry.PickedTeamID != game.HomeTeamID && entry.PickedTeamID != game.AwayTeamID {
+		return &Problem{GameID: game.ID, Code: CodeInvalidTeam, Message: "Invalid team selection for this game"}
+	}
+	if entry.PickedOverUnder != "over" && entry.PickedOverUnder != "under" {
+		return &Problem{GameID: game.ID, Code: CodeInvalidOverUnder, Message: "Must pick 'over' or 'under' for total"}
+	}
+	return nil
+}
+
+// CheckConfidence validates the confidence values of a user's picks for a confidence pool week
+// Values must be 1..N for the N games in the week that are still to be played, and unique across the entries
+// and the user's picks on other such games that week. Returns a problem for each entry with a bad value
+func CheckConfidence(db *gorm.DB, leagueID, userID, weekID uint, entries []Entry) ([]Problem, error) {
+	// Games that won't be played this week don't take a value
+	notPlayed := []string{models.GameStatusPostponed, models.GameStatusCancelled}
+	var gameCount int64
+	if err := db.Model(&models.Game{}).Where("week_id = ? AND status NOT IN ?", weekID, notPlayed).Count(&gameCount).Error; err != nil {
+		return nil, err
+	}
+
+	gameIDs := make([]uint, 0, len(entries))
+	for _, entry := range entries {
+		gameIDs = append(gameIDs, entry.GameID)
+	}
+
+	// Values already used on games this batch doesn't touch
+	var used []int
+	if err := db.Model(&models.Pick{}).
+		Joins("JOIN games ON games.id = picks.game_id").
+		Where("picks.league_id = ? AND picks.user_id = ? AND games.week_id = ? AND games.status NOT IN ? AND picks.game_id NOT IN ?", leagueID, userID, weekID, notPlayed, gameIDs).
+		Pluck("picks.confidence", &used).Error; err != nil {
+		return nil, err
+	}
+
+	var problems []Problem
+	for i, entry := range entries {
+		others := append([]int{}, used...)
+		for j, other := range entries {
+			if j != i {
+				others = append(others, other.Confidence)
+			}
+		}
+		if valErr := validation.ValidateConfidence(entry.Confidence, int(gameCount), others); valErr != nil {
+			problems = append(problems, Problem{GameID: entry.GameID, Code: CodeInvalidConfidence, Message: valErr.Details["confidence"]})
+		}
+	}
+	return problems, nil
+}
+
+// CheckWeek validates a batch of a user's picks for games in one week
+// Returns the week's games keyed by ID and a problem for each entry that can't be saved; the batch should
+// only be saved if there are none
+func CheckWeek(db *gorm.DB, week *models.Week, leagueID, userID uint, settings models.LeagueSettings, entries []Entry, now time.Time) (map[uint]*models.Game, []Problem, error) {
+	var weekGames []models.Game
+	if err := db.Where("week_id = ?", week.ID).Find(&weekGames).Error; err != nil {
+		return nil, nil, err
+	}
+	games := make(map[uint]*models.Game, len(weekGames))
+	for i := range weekGames {
+		weekGames[i].Week = *week
+		games[weekGames[i].ID] = &weekGames[i]
+	}
+
+	problems := []Problem{}
+	seen := make(map[uint]bool)
+	for _, entry := range entries {
+		game, ok := games[entry.GameID]
+		switch {
+		case seen[entry.GameID]:
+			problems = append(problems, Problem{GameID: entry.GameID, Code: CodeDuplicateGame, Message: "Game is picked more than once"})
+		case !ok:
+			problems = append(problems, Problem{GameID: entry.GameID, Code: CodeGameNotFound, Message: "Game is not part of this week"})
+		default:
+			if problem := Check(game, entry, settings, now); problem != nil {
+				problems = append(problems, *problem)
+			}
+		}
+		seen[entry.GameID] = true
+	}
+
+	if settings.Format == models.LeagueFormatConfidence {
+		confidenceProblems, err := CheckConfidence(db, leagueID, userID, week.ID, entries)
+		if err != nil {
+			return nil, nil, err
+		}
+		problems = append(problems, confidenceProblems...)
+	}
+
+	return games, problems, nil
+}
+
+// Audit is who changed a pick and where the request came from
+type Audit struct {
+	Actor    weeks.Actor
+	IP       string
+	Override bool // The league's commissioner changed the pick for the member
+	Auto     bool // Filled in by the league's auto-pick policy
+}
+
+// Save creates or updates a user's pick on a game and appends the change to the pick's history
+// The pick keeps the line shown when it was made, so later line moves don't change how it's graded.
+// A pick made again after being voided (e.g. its game was postponed) counts again. created is false for updates.
+// The standings aren't touched; call leaderboard.RefreshMembers once all of a request's picks are saved
+func Save(db *gorm.DB, leagueID, userID uint, game *models.Game, entry Entry, audit Audit) (pick *models.Pick, created bool, err error) {
+	homeSpread, total := game.HomeSpread, game.Total
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		pick = &models.Pick{}
+		err := tx.Where("league_id = ? AND user_id = ? AND game_id = ?", leagueID, userID, game.ID).First(pick).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		created = pick.ID == 0
+
+		change := models.PickChange{
+			LeagueID:           leagueID,
+			UserID:             userID,
+			GameID:             game.ID,
+			Action:             models.PickChangeCreate,
+			NewPickedTeamID:    entry.PickedTeamID,
+			NewPickedOverUnder: entry.PickedOverUnder,
+			NewConfidence:      entry.Confidence,
+			Source:             audit.Actor.Source,
+			ActorID:            audit.Actor.UserID,
+			Reason:             audit.Actor.Reason,
+			IP:                 audit.IP,
+			Override:           audit.Override,
+		}
+		if !created {
+			oldTeamID, oldOverUnder, oldConfidence := pick.PickedTeamID, pick.PickedOverUnder, pick.Confidence
+			change.Action = models.PickChangeUpdate
+			change.OldPickedTeamID = &oldTeamID
+			change.OldPickedOverUnder = &oldOverUnder
+			change.OldConfidence = &oldConfidence
+		}
+
+		pick.LeagueID = leagueID
+		pick.UserID = userID
+		pick.GameID = game.ID
+		pick.PickedTeamID = entry.PickedTeamID
+		pick.PickedOverUnder = entry.PickedOverUnder
+		pick.Confidence = entry.Confidence
+		pick.HomeSpread = &homeSpread
+		pick.Total = &total
+		pick.SpreadOutcome = models.PickOutcomePending
+		pick.OverUnderOutcome = models.PickOutcomePending
+		pick.PointsEarned = 0
+		pick.CommissionerEntered = audit.Override
+		pick.AutoPicked = audit.Auto
+		pick.EnteredByID = nil
+		if audit.Override {
+			pick.EnteredByID = audit.Actor.UserID
+		}
+
+		if err := tx.Save(pick).Error; err != nil {
+			return err
+		}
+
+		change.PickID = pick.ID
+		return tx.Create(&change).Error
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return pick, created, nil
+}
+
+// Override creates or changes a member's pick on the commissioner's behalf
+// A pick on a game that's already final is graded straight away with the league's rules.
+// Call CheckOverride first, and leaderboard.RefreshMembers afterwards. This function should be called within a transaction
+func Override(tx *gorm.DB, leagueID, userID uint, game *models.Game, entry Entry, audit Audit) (*models.Pick, bool, error) {
+	audit.Override = true
+	pick, created, err := Save(tx, leagueID, userID, game, entry, audit)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := grade(tx, leagueID, game, pick); err != nil {
+		return nil, false, err
+	}
+	return pick, created, nil
+}
+
+// grade scores a pick saved on a game that's already final
+// Picks on games still to be played are left pending for the result
+func grade(tx *gorm.DB, leagueID uint, game *models.Game, pick *models.Pick) error {
+	if !game.IsFinal || game.HomeScore == nil || game.AwayScore == nil {
+		return nil
+	}
+	rules, err := scoring.RulesForLeague(tx, leagueID)
+	if err != nil {
+		return err
+	}
+	rules.Score(game, pick)
+	return tx.Omit(clause.Associations).Save(pick).Error
+}
@@ -0,0 +1,463 @@
+package picks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/scoring"
+	"github.com/ckinger23/mountaintop/internal/weeks"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupTestDB creates an in-memory SQLite database for testing
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+
+	err = db.AutoMigrate(&models.LeagueMembership{}, &models.LeagueSettings{}, &models.Season{}, &models.Week{}, &models.Team{}, &models.Game{}, &models.Pick{}, &models.PickChange{}, &models.TiebreakerGuess{}, &models.Standing{})
+	if err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	return db
+}
+
+// seedWeek creates a picking week with three games kicking off in a day
+func seedWeek(t *testing.T, db *gorm.DB) (models.Week, []models.Game) {
+	week := models.Week{SeasonID: 1, WeekNumber: 1, Name: "Week 1", Status: "picking"}
+	db.Create(&week)
+
+	kickoff := time.Now().Add(24 * time.Hour)
+	games := []models.Game{
+		{WeekID: week.ID, HomeTeamID: 1, AwayTeamID: 2, GameTime: kickoff, HomeSpread: -7, Total: 50.5},
+		{WeekID: week.ID, HomeTeamID: 3, AwayTeamID: 4, GameTime: kickoff, HomeSpread: 3, Total: 44},
+		{WeekID: week.ID, HomeTeamID: 5, AwayTeamID: 6, GameTime: kickoff, HomeSpread: -1, Total: 61},
+	}
+	if err := db.Create(&games).Error; err != nil {
+		t.Fatalf("Failed to create games: %v", err)
+	}
+	return week, games
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Now()
+	deadline := now.Add(-time.Minute)
+	settings := scoring.DefaultSettings(1)
+	kickoffSettings := scoring.DefaultSettings(1)
+	kickoffSettings.LockAtKickoff = true
+
+	open := models.Week{Status: "picking"}
+	game := func(week models.Week, kickoff time.Time, final bool) *models.Game {
+		return &models.Game{ID: 1, HomeTeamID: 1, AwayTeamID: 2, GameTime: kickoff, IsFinal: final, Week: week}
+	}
+	valid := Entry{GameID: 1, PickedTeamID: 1, PickedOverUnder: "over"}
+
+	tests := []struct {
+		name     string
+		game     *models.Game
+		entry    Entry
+		settings models.LeagueSettings
+		wantCode string
+	}{
+		{"valid pick", game(open, now.Add(time.Hour), false), valid, settings, ""},
+		{"week not open", game(models.Week{Status: "creating"}, now.Add(time.Hour), false), valid, settings, CodePicksNotOpen},
+		{"game final", game(open, now.Add(time.Hour), true), valid, settings, CodeGameFinal},
+		{"team not in game", game(open, now.Add(time.Hour), false), Entry{GameID: 1, PickedTeamID: 9, PickedOverUnder: "over"}, settings, CodeInvalidTeam},
+		{"missing over/under", game(open, now.Add(time.Hour), false), Entry{GameID: 1, PickedTeamID: 2}, settings, CodeInvalidOverUnder},
+		{"deadline passed", game(models.Week{Status: "picking", PickDeadline: &deadline}, now.Add(time.Hour), false), valid, settings, CodeDeadlinePassed},
+		{"kicked off", game(open, now.Add(-time.Minute), false), valid, kickoffSettings, CodeKickedOff},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := Check(tt.game, tt.entry, tt.settings, now)
+			if tt.wantCode == "" {
+				assert.Nil(t, problem)
+				return
+			}
+			if assert.NotNil(t, problem) {
+				assert.Equal(t, tt.wantCode, problem.Code)
+			}
+		})
+	}
+}
+
+func TestCheckWeek(t *testing.T) {
+	db := setupTestDB(t)
+	week, games := seedWeek(t, db)
+	settings := scoring.DefaultSettings(1)
+
+	_, problems, err := CheckWeek(db, &week, 1, 1, settings, []Entry{
+		{GameID: games[0].ID, PickedTeamID: 1, PickedOverUnder: "over"},
+		{GameID: games[0].ID, PickedTeamID: 2, PickedOverUnder: "over"},
+		{GameID: 99, PickedTeamID: 1, PickedOverUnder: "over"},
+		{GameID: games[1].ID, PickedTeamID: 1, PickedOverUnder: "over"},
+	}, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, []Problem{
+		{GameID: games[0].ID, Code: CodeDuplicateGame, Message: "Game is picked more than once"},
+		{GameID: 99, Code: CodeGameNotFound, Message: "Game is not part of this week"},
+		{GameID: games[1].ID, Code: CodeInvalidTeam, Message: "Invalid team selection for this game"},
+	}, problems)
+}
+
+func TestCheckWeek_Confidence(t *testing.T) {
+	db := setupTestDB(t)
+	week, games := seedWeek(t, db)
+	settings := scoring.DefaultSettings(1)
+	settings.Format = models.LeagueFormatConfidence
+
+	// Confidence 3 is already used on the third game, which this batch doesn't change
+	db.Create(&models.Pick{LeagueID: 1, UserID: 1, GameID: games[2].ID, PickedTeamID: 5, PickedOverUnder: "over", Confidence: 3})
+
+	_, problems, err := CheckWeek(db, &week, 1, 1, settings, []Entry{
+		{GameID: games[0].ID, PickedTeamID: 1, PickedOverUnder: "over", Confidence: 3},
+		{GameID: games[1].ID, PickedTeamID: 3, PickedOverUnder: "over", Confidence: 2},
+	}, time.Now())
+	assert.NoError(t, err)
+	if assert.Len(t, problems, 1) {
+		assert.Equal(t, games[0].ID, problems[0].GameID)
+		assert.Equal(t, CodeInvalidConfidence, problems[0].Code)
+	}
+
+	// Swapping values between games in the same batch is fine
+	_, problems, err = CheckWeek(db, &week, 1, 1, settings, []Entry{
+		{GameID: games[0].ID, PickedTeamID: 1, PickedOverUnder: "over", Confidence: 1},
+		{GameID: games[1].ID, PickedTeamID: 3, PickedOverUnder: "over", Confidence: 2},
+	}, time.Now())
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+
+	// A postponed game takes no value, so there are two to hand out and its pick's 3 is free again
+	db.Model(&games[2]).Update("status", models.GameStatusPostponed)
+	_, problems, err = CheckWeek(db, &week, 1, 1, settings, []Entry{
+		{GameID: games[0].ID, PickedTeamID: 1, PickedOverUnder: "over", Confidence: 3},
+		{GameID: games[1].ID, PickedTeamID: 3, PickedOverUnder: "over", Confidence: 1},
+	}, time.Now())
+	assert.NoError(t, err)
+	if assert.Len(t, problems, 1) {
+		assert.Equal(t, games[0].ID, problems[0].GameID)
+	}
+	_, problems, err = CheckWeek(db, &week, 1, 1, settings, []Entry{
+		{GameID: games[0].ID, PickedTeamID: 1, PickedOverUnder: "over", Confidence: 2},
+		{GameID: games[1].ID, PickedTeamID: 3, PickedOverUnder: "over", Confidence: 1},
+	}, time.Now())
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+}
+
+func TestSave(t *testing.T) {
+	db := setupTestDB(t)
+	_, games := seedWeek(t, db)
+	audit := Audit{Actor: weeks.UserActor(1), IP: "203.0.113.9"}
+
+	pick, created, err := Save(db, 1, 1, &games[0], Entry{GameID: games[0].ID, PickedTeamID: 1, PickedOverUnder: "over"}, audit)
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, -7.0, *pick.HomeSpread)
+
+	// Picking again updates the same row with the current line and clears a voided outcome
+	db.Model(&models.Pick{}).Where("id = ?", pick.ID).Update("spread_outcome", models.PickOutcomeVoid)
+	games[0].HomeSpread = -9.5
+
+	updated, created, err := Save(db, 1, 1, &games[0], Entry{GameID: games[0].ID, PickedTeamID: 2, PickedOverUnder: "under"}, audit)
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, pick.ID, updated.ID)
+	assert.Equal(t, uint(2), updated.PickedTeamID)
+	assert.Equal(t, -9.5, *updated.HomeSpread)
+	assert.Equal(t, models.PickOutcomePending, updated.SpreadOutcome)
+
+	// Both saves are kept in the pick's history
+	var changes []models.PickChange
+	db.Where("pick_id = ?", pick.ID).Order("id").Find(&changes)
+	if assert.Len(t, changes, 2) {
+		assert.Equal(t, models.PickChangeCreate, changes[0].Action)
+		assert.Nil(t, changes[0].OldPickedTeamID)
+		assert.Equal(t, uint(1), changes[0].NewPickedTeamID)
+		assert.Equal(t, "203.0.113.9", changes[0].IP)
+		assert.Equal(t, weeks.SourceUser, changes[0].Source)
+
+		assert.Equal(t, models.PickChangeUpdate, changes[1].Action)
+		assert.Equal(t, uint(1), *changes[1].OldPickedTeamID)
+		assert.Equal(t, "over", *changes[1].OldPickedOverUnder)
+		assert.Equal(t, uint(2), changes[1].NewPickedTeamID)
+		assert.Equal(t, "under", changes[1].NewPickedOverUnder)
+	}
+}
+
+func TestCheckGuess(t *testing.T) {
+	db := setupTestDB(t)
+	week, games := seedWeek(t, db)
+	settings := scoring.DefaultSettings(1)
+	settings.LockAtKickoff = true
+	now := time.Now()
+
+	problem, err := CheckGuess(db, &week, 48, settings, now)
+	assert.NoError(t, err)
+	if assert.NotNil(t, problem) {
+		assert.Equal(t, CodeNoTiebreakerGame, problem.Code)
+	}
+
+	week.TiebreakerGameID = &games[1].ID
+	problem, err = CheckGuess(db, &week, 48, settings, now)
+	assert.NoError(t, err)
+	assert.Nil(t, problem)
+
+	problem, err = CheckGuess(db, &week, -1, settings, now)
+	assert.NoError(t, err)
+	if assert.NotNil(t, problem) {
+		assert.Equal(t, games[1].ID, problem.GameID)
+		assert.Equal(t, CodeInvalidGuess, problem.Code)
+	}
+
+	// The guess locks when the tiebreaker game kicks off
+	problem, err = CheckGuess(db, &week, 48, settings, now.Add(48*time.Hour))
+	assert.NoError(t, err)
+	if assert.NotNil(t, problem) {
+		assert.Equal(t, CodeKickedOff, problem.Code)
+	}
+}
+
+func TestSaveGuess(t *testing.T) {
+	db := setupTestDB(t)
+	week, _ := seedWeek(t, db)
+
+	guess, err := SaveGuess(db, 1, 1, week.ID, 48)
+	assert.NoError(t, err)
+
+	// Guessing again replaces the guess
+	updated, err := SaveGuess(db, 1, 1, week.ID, 55)
+	assert.NoError(t, err)
+	assert.Equal(t, guess.ID, updated.ID)
+
+	var guesses []models.TiebreakerGuess
+	db.Find(&guesses)
+	if assert.Len(t, guesses, 1) {
+		assert.Equal(t, 55, guesses[0].TotalPoints)
+	}
+}
+
+// seedLeagueGame creates a league the user belongs to, with one game in a week of the given status
+func seedLeagueGame(db *gorm.DB, leagueID, userID uint, status string, game models.Game) models.Game {
+	db.Create(&models.LeagueMembership{LeagueID: leagueID, UserID: userID, JoinedAt: time.Now()})
+	season := models.Season{LeagueID: leagueID, Year: 2024}
+	db.Create(&season)
+	week := models.Week{SeasonID: season.ID, WeekNumber: 1, Status: status}
+	db.Create(&week)
+	game.WeekID = week.ID
+	db.Create(&game)
+	return game
+}
+
+func TestSaveToLeagues(t *testing.T) {
+	db := setupTestDB(t)
+	kickoff := time.Date(2024, 9, 7, 19, 30, 0, 0, time.UTC)
+	scheduledID := uint(40)
+	same := models.Game{HomeTeamID: 1, AwayTeamID: 2, GameTime: kickoff, HomeSpread: -3, Total: 48}
+
+	source := seedLeagueGame(db, 1, 7, "picking", same)
+	open := seedLeagueGame(db, 2, 7, "picking", same)
+	closed := seedLeagueGame(db, 3, 7, "creating", same)
+	linked := seedLeagueGame(db, 4, 7, "picking", models.Game{HomeTeamID: 1, AwayTeamID: 2, GameTime: kickoff.Add(time.Hour), ScheduledGameID: &scheduledID})
+	seedLeagueGame(db, 5, 7, "picking", models.Game{HomeTeamID: 1, AwayTeamID: 3, GameTime: kickoff})
+	seedLeagueGame(db, 6, 8, "picking", same) // Not the user's league
+
+	db.Create(&models.LeagueSettings{LeagueID: 2, Format: models.LeagueFormatConfidence})
+	source.ScheduledGameID = &scheduledID
+
+	games, err := OtherLeagueGames(db, &source, 1, 7)
+	assert.NoError(t, err)
+	var ids []uint
+	for _, g := range games {
+		ids = append(ids, g.ID)
+	}
+	assert.Equal(t, []uint{open.ID, closed.ID, linked.ID}, ids)
+
+	results, err := SaveToLeagues(db, games, 7, Entry{GameID: source.ID, PickedTeamID: 2, PickedOverUnder: "under"}, Audit{Actor: weeks.UserActor(7)}, kickoff.Add(-time.Hour))
+	assert.NoError(t, err)
+	if assert.Len(t, results, 3) {
+		// League 2 is a confidence pool and the pick has no confidence value
+		assert.False(t, results[0].Accepted)
+		assert.Equal(t, CodeInvalidConfidence, results[0].Code)
+		assert.False(t, results[1].Accepted)
+		assert.Equal(t, CodePicksNotOpen, results[1].Code)
+		assert.True(t, results[2].Accepted)
+		assert.Equal(t, uint(4), results[2].Pick.LeagueID)
+		assert.Equal(t, linked.ID, results[2].Pick.GameID)
+	}
+
+	var count int64
+	db.Model(&models.Pick{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestOverride(t *testing.T) {
+	db := setupTestDB(t)
+	_, games := seedWeek(t, db)
+
+	// Home team won by 10 against a 7 point spread, 52 total points
+	homeScore, awayScore := 31, 21
+	games[0].IsFinal = true
+	games[0].Status = models.GameStatusFinal
+	games[0].HomeScore = &homeScore
+	games[0].AwayScore = &awayScore
+	db.Save(&games[0])
+
+	assert.Nil(t, CheckOverride(&games[0], Entry{GameID: games[0].ID, PickedTeamID: 1, PickedOverUnder: "over"}))
+	games[1].Status = models.GameStatusPostponed
+	assert.Equal(t, CodeGameNotPlayed, CheckOverride(&games[1], Entry{GameID: games[1].ID, PickedTeamID: 3, PickedOverUnder: "over"}).Code)
+
+	commissioner := uint(9)
+	pick, created, err := Override(db, 1, 2, &games[0], Entry{GameID: games[0].ID, PickedTeamID: 1, PickedOverUnder: "over"},
+		Audit{Actor: weeks.Actor{Source: weeks.SourceUser, UserID: &commissioner, Reason: "Texted picks Friday"}})
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.True(t, pick.CommissionerEntered)
+	assert.Equal(t, commissioner, *pick.EnteredByID)
+
+	// The game is already final, so the pick is graded straight away
+	assert.Equal(t, models.PickOutcomeWin, pick.SpreadOutcome)
+	assert.Equal(t, models.PickOutcomeWin, pick.OverUnderOutcome)
+	assert.Equal(t, 2, pick.PointsEarned)
+
+	var change models.PickChange
+	db.Where("pick_id = ?", pick.ID).First(&change)
+	assert.True(t, change.Override)
+	assert.Equal(t, "Texted picks Friday", change.Reason)
+
+	// The member changing the pick themselves clears the flag
+	updated, _, err := Save(db, 1, 2, &games[0], Entry{GameID: games[0].ID, PickedTeamID: 2, PickedOverUnder: "over"}, Audit{Actor: weeks.UserActor(2)})
+	assert.NoError(t, err)
+	assert.False(t, updated.CommissionerEntered)
+	assert.Nil(t, updated.EnteredByID)
+}
+
+func TestChooseEntry(t *testing.T) {
+	homeFavored := &models.Game{ID: 1, HomeTeamID: 1, AwayTeamID: 2, HomeSpread: -6.5}
+	awayFavored := &models.Game{ID: 1, HomeTeamID: 1, AwayTeamID: 2, HomeSpread: 3}
+	split := consensus{favorites: 2, underdogs: 2, under: 3, over: 1}
+	underdogs := consensus{favorites: 1, underdogs: 3, over: 4}
+
+	tests := []struct {
+		name      string
+		policy    string
+		game      *models.Game
+		tally     consensus
+		wantTeam  uint
+		wantTotal string
+	}{
+		{"favorite at home", models.AutoPickFavorite, homeFavored, consensus{}, 1, "over"},
+		{"favorite on the road", models.AutoPickFavorite, awayFavored, consensus{}, 2, "over"},
+		{"home team", models.AutoPickHome, awayFavored, consensus{}, 1, "over"},
+		{"underdog", models.AutoPickUnderdog, homeFavored, consensus{}, 2, "over"},
+		{"consensus", models.AutoPickConsensus, homeFavored, underdogs, 2, "over"},
+		{"split consensus falls back to the favorite", models.AutoPickConsensus, awayFavored, split, 2, "under"},
+		{"no picks falls back to the favorite", models.AutoPickConsensus, homeFavored, consensus{}, 1, "over"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := chooseEntry(tt.policy, tt.game, tt.tally)
+			assert.Equal(t, tt.wantTeam, entry.PickedTeamID)
+			assert.Equal(t, tt.wantTotal, entry.PickedOverUnder)
+		})
+	}
+}
+
+func TestLastWeekConsensus(t *testing.T) {
+	db := setupTestDB(t)
+	season := models.Season{LeagueID: 1, Year: 2024}
+	db.Create(&season)
+	week1 := models.Week{SeasonID: season.ID, WeekNumber: 1, Status: "finished"}
+	week2 := models.Week{SeasonID: season.ID, WeekNumber: 2, Status: "locked"}
+	db.Create(&week1)
+	db.Create(&week2)
+
+	// The home team was favored when the picks were made, before the line flipped
+	game := models.Game{WeekID: week1.ID, HomeTeamID: 1, AwayTeamID: 2, HomeSpread: 3}
+	db.Create(&game)
+	madeAt := -2.5
+	for userID, teamID := range map[uint]uint{1: 2, 2: 2, 3: 1} {
+		db.Create(&models.Pick{LeagueID: 1, UserID: userID, GameID: game.ID, PickedTeamID: teamID, PickedOverUnder: "under", HomeSpread: &madeAt})
+	}
+	// Auto-picks and other leagues' picks don't count
+	db.Create(&models.Pick{LeagueID: 1, UserID: 4, GameID: game.ID, PickedTeamID: 1, PickedOverUnder: "over", HomeSpread: &madeAt, AutoPicked: true})
+	db.Create(&models.Pick{LeagueID: 2, UserID: 5, GameID: game.ID, PickedTeamID: 1, PickedOverUnder: "over", HomeSpread: &madeAt})
+
+	tally, err := lastWeekConsensus(db, 1, &week2)
+	assert.NoError(t, err)
+	assert.Equal(t, consensus{favorites: 1, underdogs: 2, under: 3}, tally)
+
+	// The first week of a season has nothing to copy
+	tally, err = lastWeekConsensus(db, 1, &week1)
+	assert.NoError(t, err)
+	assert.Equal(t, consensus{}, tally)
+}
+
+func TestAutoPick(t *testing.T) {
+	db := setupTestDB(t)
+	season := models.Season{LeagueID: 1, Year: 2024}
+	db.Create(&season)
+	week := models.Week{SeasonID: season.ID, WeekNumber: 1, Status: "scoring", Season: season}
+	db.Create(&week)
+
+	kickoff := time.Now().Add(-3 * time.Hour)
+	homeScore, awayScore := 17, 24
+	games := []models.Game{
+		{WeekID: week.ID, HomeTeamID: 1, AwayTeamID: 2, GameTime: kickoff, HomeSpread: -3, Total: 40, IsFinal: true, Status: models.GameStatusFinal, HomeScore: &homeScore, AwayScore: &awayScore},
+		{WeekID: week.ID, HomeTeamID: 3, AwayTeamID: 4, GameTime: kickoff.Add(time.Hour), HomeSpread: 7, Total: 55},
+		{WeekID: week.ID, HomeTeamID: 5, AwayTeamID: 6, GameTime: kickoff.Add(time.Hour), Status: models.GameStatusPostponed},
+	}
+	db.Create(&games)
+	for _, userID := range []uint{1, 2} {
+		db.Create(&models.LeagueMembership{LeagueID: 1, UserID: userID, JoinedAt: time.Now()})
+	}
+	db.Create(&models.Pick{LeagueID: 1, UserID: 1, GameID: games[1].ID, PickedTeamID: 3, PickedOverUnder: "under", Confidence: 1})
+
+	// No policy leaves missed games empty
+	settings := scoring.DefaultSettings(1)
+	db.Create(&settings)
+	autoPicks, err := AutoPick(db, &week, weeks.SchedulerActor("deadline passed"))
+	assert.NoError(t, err)
+	assert.Empty(t, autoPicks)
+
+	db.Model(&settings).Updates(map[string]interface{}{"auto_pick": models.AutoPickFavorite, "format": models.LeagueFormatConfidence})
+	autoPicks, err = AutoPick(db, &week, weeks.SchedulerActor("deadline passed"))
+	assert.NoError(t, err)
+	if !assert.Len(t, autoPicks, 3) {
+		return
+	}
+
+	// User 1 only missed the final game; it gets the lowest unused confidence and is graded right away
+	assert.Equal(t, uint(1), autoPicks[0].UserID)
+	assert.Equal(t, games[0].ID, autoPicks[0].GameID)
+	assert.Equal(t, uint(1), autoPicks[0].PickedTeamID)
+	assert.Equal(t, 2, autoPicks[0].Confidence)
+	assert.True(t, autoPicks[0].AutoPicked)
+	assert.Equal(t, models.PickOutcomeLoss, autoPicks[0].SpreadOutcome)
+	assert.Equal(t, models.PickOutcomeWin, autoPicks[0].OverUnderOutcome)
+
+	// User 2 missed both games that are being played, and takes the road favorite in the second
+	assert.Equal(t, uint(2), autoPicks[1].UserID)
+	assert.Equal(t, 1, autoPicks[1].Confidence)
+	assert.Equal(t, games[1].ID, autoPicks[2].GameID)
+	assert.Equal(t, uint(4), autoPicks[2].PickedTeamID)
+	assert.Equal(t, 2, autoPicks[2].Confidence)
+	assert.Equal(t, models.PickOutcomePending, autoPicks[2].SpreadOutcome)
+
+	var change models.PickChange
+	db.Where("pick_id = ?", autoPicks[0].ID).First(&change)
+	assert.Equal(t, weeks.SourceScheduler, change.Source)
+	assert.Equal(t, "auto-pick: favorite", change.Reason)
+
+	// Running again finds nothing missing
+	autoPicks, err = AutoPick(db, &week, weeks.SchedulerActor("deadline passed"))
+	assert.NoError(t, err)
+	assert.Empty(t, autoPicks)
+}
@@ -0,0 +1,267 @@
+package results
+
+import (
+	"errors"
+
+	"github.com/ckinger23/mountaintop/internal/bracket"
+	"github.com/ckinger23/mountaintop/internal/leaderboard"
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/scoring"
+	"github.com/ckinger23/mountaintop/internal/survivor"
+	"github.com/ckinger23/mountaintop/internal/weeks"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrWeekFinished is returned when a result in a finished week is changed without an override
+var ErrWeekFinished = errors.New("week is finished")
+
+// ErrGameNotPlayed is returned when a result is submitted for a postponed or cancelled game
+var ErrGameNotPlayed = errors.New("game is postponed or cancelled")
+
+// Submission is a score reported for a game
+type Submission struct {
+	HomeScore int
+	AwayScore int
+	IsFinal   bool
+	Override  bool // Allows changing a result after the game's week is finished
+}
+
+// LeaderboardChange is one user's leaderboard movement caused by a corrected result
+type LeaderboardChange struct {
+	LeagueID     uint   `json:"league_id"`
+	UserID       uint   `json:"user_id"`
+	Username     string `json:"username"`
+	PointsBefore int    `json:"points_before"`
+	PointsAfter  int    `json:"points_after"`
+	RankBefore   int    `json:"rank_before"`
+	RankAfter    int    `json:"rank_after"`
+}
+
+// Outcome describes what recording a result changed
+type Outcome struct {
+	Correction         bool                `json:"is_correction"`                 // The game was already final
+	WeekReopened       bool                `json:"week_reopened"`                 // A finished week went back to 'scoring'
+	LeaderboardChanges []LeaderboardChange `json:"leaderboard_changes,omitempty"` // Only set for corrections
+}
+
+// Record saves a submitted score for a game, adds it to the game's result history and rescores every pick on it
+// Changing a game that was already final is a correction: the leaderboard movement it causes is returned,
+// and a finished week is reopened for scoring if the game is no longer final.
+// game must have its Week loaded. This function should be called within a transaction
+func Record(tx *gorm.DB, game *models.Game, sub Submission, actor weeks.Actor) (*Outcome, error) {
+	if status := statusOf(game); status == models.GameStatusPostponed || status == models.GameStatusCancelled {
+		return nil, ErrGameNotPlayed
+	}
+	if game.Week.Status == "finished" && !sub.Override {
+		return nil, ErrWeekFinished
+	}
+
+	outcome := &Outcome{Correction: game.IsFinal}
+
+	// Snapshot the leaderboards this game counts toward before anything changes
+	var leagueIDs []uint
+	before := make(map[uint][]models.LeaderboardEntry)
+	if outcome.Correction {
+		if err := tx.Model(&models.Pick{}).Where("game_id = ?", game.ID).Distinct().Pluck("league_id", &leagueIDs).Error; err != nil {
+			return nil, err
+		}
+		for _, leagueID := range leagueIDs {
+			entries, err := leaderboard.NewQuery(tx).ForSeason(game.Week.SeasonID).ForLeague(leagueID).Execute()
+			if err != nil {
+				return nil, err
+			}
+			before[leagueID] = entries
+		}
+	}
+
+	// Update game result
+	homeScore, awayScore := sub.HomeScore, sub.AwayScore
+	game.HomeScore = &homeScore
+	game.AwayScore = &awayScore
+	game.IsFinal = sub.IsFinal
+	game.Status = models.GameStatusInProgress
+	if sub.IsFinal {
+		game.Status = models.GameStatusFinal
+	}
+
+	// Determine winner
+	if homeScore > awayScore {
+		game.WinnerTeamID = &game.HomeTeamID
+	} else if awayScore > homeScore {
+		game.WinnerTeamID = &game.AwayTeamID
+	} else {
+		// If tied, WinnerTeamID remains nil
+		game.WinnerTeamID = nil
+	}
+
+	if err := tx.Omit(clause.Associations).Save(game).Error; err != nil {
+		return nil, err
+	}
+
+	history := models.GameResult{
+		GameID:       game.ID,
+		HomeScore:    homeScore,
+		AwayScore:    awayScore,
+		IsFinal:      sub.IsFinal,
+		IsCorrection: outcome.Correction,
+		Source:       actor.Source,
+		UserID:       actor.UserID,
+		Reason:       actor.Reason,
+	}
+	if err := tx.Create(&history).Error; err != nil {
+		return nil, err
+	}
+
+	if game.IsFinal {
+		if err := ScorePicks(tx, game.ID); err != nil {
+			return nil, err
+		}
+
+		// Survivor picks on this game drive eliminations
+		if err := survivor.GradeGame(tx, game); err != nil {
+			return nil, err
+		}
+
+		// Grade bracket picks and advance the winner to the next round
+		if err := bracket.AdvanceGame(tx, game); err != nil {
+			return nil, err
+		}
+	} else if outcome.Correction {
+		// The game is no longer final, so its picks go back to pending
+		if err := resetPicks(tx, game.ID); err != nil {
+			return nil, err
+		}
+
+		if game.Week.Status == "finished" {
+			if err := weeks.Reopen(tx, &game.Week, actor); err != nil {
+				return nil, err
+			}
+			outcome.WeekReopened = true
+		}
+	}
+
+	for _, leagueID := range leagueIDs {
+		after, err := leaderboard.NewQuery(tx).ForSeason(game.Week.SeasonID).ForLeague(leagueID).Execute()
+		if err != nil {
+			return nil, err
+		}
+		outcome.LeaderboardChanges = append(outcome.LeaderboardChanges, Diff(leagueID, before[leagueID], after)...)
+	}
+
+	return outcome, nil
+}
+
+// ScorePicks updates all picks for a game once it's final
+// This function should be called within a transaction
+func ScorePicks(tx *gorm.DB, gameID uint) error {
+	var game models.Game
+	if err := tx.First(&game, gameID).Error; err != nil {
+		return err
+	}
+
+	// Verify game has scores and winner
+	if game.HomeScore == nil || game.AwayScore == nil {
+		return nil // Nothing to calculate without scores
+	}
+
+	// Voided picks (e.g. on a game postponed into another week) stay void
+	var picks []models.Pick
+	if err := tx.Where("game_id = ? AND spread_outcome <> ?", gameID, models.PickOutcomeVoid).Find(&picks).Error; err != nil {
+		return err
+	}
+
+	// Each league scores with its own rules
+	rulesByLeague := make(map[uint]scoring.Rules)
+
+	for i := range picks {
+		rules, ok := rulesByLeague[picks[i].LeagueID]
+		if !ok {
+			var err error
+			rules, err = scoring.RulesForLeague(tx, picks[i].LeagueID)
+			if err != nil {
+				return err
+			}
+			rulesByLeague[picks[i].LeagueID] = rules
+		}
+
+		// Grade spread and over/under picks and award points
+		rules.Score(&game, &picks[i])
+
+		// Save each pick within the transaction
+		if err := tx.Save(&picks[i]).Error; err != nil {
+			return err
+		}
+	}
+
+	return leaderboard.RefreshWeek(tx, game.WeekID)
+}
+
+// resetPicks clears the graded results of every pick on a game
+func resetPicks(tx *gorm.DB, gameID uint) error {
+	if err := tx.Model(&models.Pick{}).Where("game_id = ? AND spread_outcome <> ?", gameID, models.PickOutcomeVoid).Updates(map[string]interface{}{
+		"spread_outcome":     models.PickOutcomePending,
+		"over_under_outcome": models.PickOutcomePending,
+		"points_earned":      0,
+	}).Error; err != nil {
+		return err
+	}
+	if err := leaderboard.RefreshGame(tx, gameID); err != nil {
+		return err
+	}
+
+	if err := tx.Model(&models.SurvivorPick{}).Where("game_id = ? AND outcome <> ?", gameID, models.PickOutcomeVoid).
+		Update("outcome", models.PickOutcomePending).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&models.BracketPick{}).
+		Where("slot_id IN (?)", tx.Model(&models.BracketSlot{}).Select("id").Where("game_id = ?", gameID)).
+		Updates(map[string]interface{}{
+			"outcome":       models.PickOutcomePending,
+			"points_earned": 0,
+		}).Error
+}
+
+// Diff returns the users whose points or rank differ between two leaderboards
+// Users tied on points share a rank, so ties don't show up as movement
+func Diff(leagueID uint, before, after []models.LeaderboardEntry) []LeaderboardChange {
+	type standing struct {
+		points int
+		rank   int
+	}
+	previous := make(map[uint]standing, len(before))
+	for _, entry := range before {
+		previous[entry.UserID] = standing{points: entry.TotalPoints, rank: rankOf(before, entry.TotalPoints)}
+	}
+
+	var changes []LeaderboardChange
+	for _, entry := range after {
+		rank := rankOf(after, entry.TotalPoints)
+		was, ok := previous[entry.UserID]
+		if ok && was.points == entry.TotalPoints && was.rank == rank {
+			continue
+		}
+		changes = append(changes, LeaderboardChange{
+			LeagueID:     leagueID,
+			UserID:       entry.UserID,
+			Username:     entry.Username,
+			PointsBefore: was.points,
+			PointsAfter:  entry.TotalPoints,
+			RankBefore:   was.rank,
+			RankAfter:    rank,
+		})
+	}
+	return changes
+}
+
+// rankOf returns the 1-based rank for a point total: one more than the number of entries ahead of it
+func rankOf(entries []models.LeaderboardEntry, points int) int {
+	rank := 1
+	for _, entry := range entries {
+		if entry.TotalPoints > points {
+			rank++
+		}
+	}
+	return rank
+}
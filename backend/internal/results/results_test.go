@@ -0,0 +1,268 @@
+package results
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ckinger23/mountaintop/internal/leaderboard"
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/scoring"
+	"github.com/ckinger23/mountaintop/internal/weeks"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupTestDB creates an in-memory SQLite database for testing
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+
+	err = db.AutoMigrate(
+		&models.League{},
+		&models.LeagueMembership{},
+		&models.LeagueSettings{},
+		&models.User{},
+		&models.Season{},
+		&models.Week{},
+		&models.WeekTransition{},
+		&models.Team{},
+		&models.ScheduledGame{},
+		&models.Game{},
+		&models.GameResult{},
+		&models.Pick{},
+		&models.Standing{},
+		&models.SurvivorPick{},
+		&models.BracketSlot{},
+		&models.BracketPick{},
+	)
+	if err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	return db
+}
+
+// fixture holds the records created by seedGame
+type fixture struct {
+	week  models.Week
+	game  models.Game
+	alice models.User
+	bob   models.User
+}
+
+// seedGame creates a week with one game that alice picked at home and bob picked away
+func seedGame(t *testing.T, db *gorm.DB, weekStatus string) fixture {
+	var f fixture
+
+	f.alice = models.User{Username: "alice", Email: "alice@example.com", PasswordHash: "hash"}
+	f.bob = models.User{Username: "bob", Email: "bob@example.com", PasswordHash: "hash"}
+	db.Create(&f.alice)
+	db.Create(&f.bob)
+
+	league := models.League{Name: "League", Code: "RES-1", OwnerID: f.alice.ID, IsActive: true}
+	db.Create(&league)
+	db.Create(&models.LeagueMembership{LeagueID: league.ID, UserID: f.alice.ID, Role: "owner", JoinedAt: time.Now()})
+	db.Create(&models.LeagueMembership{LeagueID: league.ID, UserID: f.bob.ID, Role: "member", JoinedAt: time.Now()})
+	season := models.Season{LeagueID: league.ID, Year: 2025}
+	db.Create(&season)
+	f.week = models.Week{SeasonID: season.ID, WeekNumber: 1, Name: "Week 1", Status: weekStatus}
+	db.Create(&f.week)
+
+	home := models.Team{Name: "Home", Abbreviation: "HOM"}
+	away := models.Team{Name: "Away", Abbreviation: "AWY"}
+	db.Create(&home)
+	db.Create(&away)
+
+	f.game = models.Game{WeekID: f.week.ID, HomeTeamID: home.ID, AwayTeamID: away.ID, HomeSpread: -3.5, Total: 40.5}
+	if err := db.Create(&f.game).Error; err != nil {
+		t.Fatalf("Failed to create game: %v", err)
+	}
+
+	db.Create(&models.Pick{LeagueID: league.ID, UserID: f.alice.ID, GameID: f.game.ID, PickedTeamID: home.ID, PickedOverUnder: "over"})
+	db.Create(&models.Pick{LeagueID: league.ID, UserID: f.bob.ID, GameID: f.game.ID, PickedTeamID: away.ID, PickedOverUnder: "over"})
+
+	db.Preload("Week").First(&f.game, f.game.ID)
+	return f
+}
+
+// record runs Record in a transaction, like the handler does
+func record(db *gorm.DB, game *models.Game, sub Submission) (*Outcome, error) {
+	var outcome *Outcome
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		outcome, err = Record(tx, game, sub, weeks.UserActor(1))
+		return err
+	})
+	return outcome, err
+}
+
+func pointsFor(t *testing.T, db *gorm.DB, userID uint) int {
+	var pick models.Pick
+	if err := db.Where("user_id = ?", userID).First(&pick).Error; err != nil {
+		t.Fatalf("Failed to load pick: %v", err)
+	}
+	return pick.PointsEarned
+}
+
+func TestRecord_Correction(t *testing.T) {
+	db := setupTestDB(t)
+	f := seedGame(t, db, "scoring")
+
+	// First final: home covers and the over hits, so alice sweeps
+	outcome, err := record(db, &f.game, Submission{HomeScore: 28, AwayScore: 17, IsFinal: true})
+	assert.NoError(t, err)
+	assert.False(t, outcome.Correction)
+	assert.Empty(t, outcome.LeaderboardChanges)
+	assert.Equal(t, 2, pointsFor(t, db, f.alice.ID))
+	assert.Equal(t, 1, pointsFor(t, db, f.bob.ID))
+
+	// Correcting the score to an away win flips the spread result
+	outcome, err = record(db, &f.game, Submission{HomeScore: 21, AwayScore: 24, IsFinal: true})
+	assert.NoError(t, err)
+	assert.True(t, outcome.Correction)
+	assert.Equal(t, 1, pointsFor(t, db, f.alice.ID))
+	assert.Equal(t, 2, pointsFor(t, db, f.bob.ID))
+
+	changes := map[uint]LeaderboardChange{}
+	for _, change := range outcome.LeaderboardChanges {
+		changes[change.UserID] = change
+	}
+	if assert.Len(t, changes, 2) {
+		assert.Equal(t, LeaderboardChange{LeagueID: 1, UserID: f.alice.ID, Username: "alice", PointsBefore: 2, PointsAfter: 1, RankBefore: 1, RankAfter: 2}, changes[f.alice.ID])
+		assert.Equal(t, LeaderboardChange{LeagueID: 1, UserID: f.bob.ID, Username: "bob", PointsBefore: 1, PointsAfter: 2, RankBefore: 2, RankAfter: 1}, changes[f.bob.ID])
+	}
+
+	// Standings are rescored in the same transaction
+	var standing models.Standing
+	db.Where("week_id = ? AND user_id = ?", f.week.ID, f.bob.ID).First(&standing)
+	assert.Equal(t, 2, standing.Points)
+
+	// Every submission is kept
+	var history []models.GameResult
+	db.Where("game_id = ?", f.game.ID).Order("id ASC").Find(&history)
+	if assert.Len(t, history, 2) {
+		assert.False(t, history[0].IsCorrection)
+		assert.True(t, history[1].IsCorrection)
+		assert.Equal(t, 21, history[1].HomeScore)
+		assert.Equal(t, weeks.SourceUser, history[1].Source)
+	}
+}
+
+func TestRecord_FinishedWeek(t *testing.T) {
+	db := setupTestDB(t)
+	f := seedGame(t, db, "scoring")
+
+	_, err := record(db, &f.game, Submission{HomeScore: 28, AwayScore: 17, IsFinal: true})
+	assert.NoError(t, err)
+	db.Model(&models.Week{}).Where("id = ?", f.week.ID).Update("status", "finished")
+	db.Preload("Week").First(&f.game, f.game.ID)
+
+	// Finished weeks need an explicit override
+	_, err = record(db, &f.game, Submission{HomeScore: 28, AwayScore: 27, IsFinal: true})
+	assert.ErrorIs(t, err, ErrWeekFinished)
+
+	var count int64
+	db.Model(&models.GameResult{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+
+	// A correction that keeps the game final leaves the week finished
+	outcome, err := record(db, &f.game, Submission{HomeScore: 28, AwayScore: 27, IsFinal: true, Override: true})
+	assert.NoError(t, err)
+	assert.False(t, outcome.WeekReopened)
+	assert.Equal(t, 1, pointsFor(t, db, f.alice.ID))
+
+	// Taking the game back off final reopens the week and clears the graded picks
+	outcome, err = record(db, &f.game, Submission{HomeScore: 28, AwayScore: 27, IsFinal: false, Override: true})
+	assert.NoError(t, err)
+	assert.True(t, outcome.WeekReopened)
+
+	var week models.Week
+	db.First(&week, f.week.ID)
+	assert.Equal(t, "scoring", week.Status)
+
+	var pick models.Pick
+	db.Where("user_id = ?", f.alice.ID).First(&pick)
+	assert.Equal(t, models.PickOutcomePending, pick.SpreadOutcome)
+	assert.Equal(t, 0, pick.PointsEarned)
+
+	var transition models.WeekTransition
+	assert.NoError(t, db.Where("week_id = ? AND from_status = ?", f.week.ID, "finished").First(&transition).Error)
+	assert.Equal(t, "scoring", transition.ToStatus)
+}
+
+func TestRecord_ConfidenceWeightedTotals(t *testing.T) {
+	db := setupTestDB(t)
+	f := seedGame(t, db, "scoring")
+
+	var alicePick models.Pick
+	db.Where("user_id = ?", f.alice.ID).First(&alicePick)
+	leagueID := alicePick.LeagueID
+	settings := scoring.DefaultSettings(leagueID)
+	settings.Format = models.LeagueFormatConfidence
+	db.Create(&settings)
+
+	// A second game with the same line; alice puts her top value on the first, bob on the second
+	second := models.Game{WeekID: f.week.ID, HomeTeamID: f.game.HomeTeamID, AwayTeamID: f.game.AwayTeamID, HomeSpread: -3.5, Total: 40.5}
+	db.Create(&second)
+	db.Model(&models.Pick{}).Where("user_id = ?", f.alice.ID).Update("confidence", 2)
+	db.Model(&models.Pick{}).Where("user_id = ?", f.bob.ID).Update("confidence", 1)
+	db.Create(&models.Pick{LeagueID: leagueID, UserID: f.alice.ID, GameID: second.ID, PickedTeamID: second.HomeTeamID, PickedOverUnder: "over", Confidence: 1})
+	db.Create(&models.Pick{LeagueID: leagueID, UserID: f.bob.ID, GameID: second.ID, PickedTeamID: second.HomeTeamID, PickedOverUnder: "under", Confidence: 2})
+	db.Preload("Week").First(&second, second.ID)
+
+	// The home team covers and the over hits in both games
+	for _, game := range []*models.Game{&f.game, &second} {
+		_, err := record(db, game, Submission{HomeScore: 28, AwayScore: 17, IsFinal: true})
+		assert.NoError(t, err)
+	}
+
+	// A covered spread earns the pick's confidence value; the over/under stays a flat point
+	points := func(userID, gameID uint) int {
+		var pick models.Pick
+		db.Where("user_id = ? AND game_id = ?", userID, gameID).First(&pick)
+		return pick.PointsEarned
+	}
+	assert.Equal(t, 3, points(f.alice.ID, f.game.ID))
+	assert.Equal(t, 2, points(f.alice.ID, second.ID))
+	assert.Equal(t, 1, points(f.bob.ID, f.game.ID))
+	assert.Equal(t, 2, points(f.bob.ID, second.ID))
+
+	entries, err := leaderboard.GetLeaderboard(db, nil, &leagueID)
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, "alice", entries[0].Username)
+		assert.Equal(t, 5, entries[0].TotalPoints)
+		assert.Equal(t, 4, entries[0].CorrectPicks)
+		assert.Equal(t, "bob", entries[1].Username)
+		assert.Equal(t, 3, entries[1].TotalPoints)
+		assert.Equal(t, 2, entries[1].CorrectPicks)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	before := []models.LeaderboardEntry{
+		{UserID: 1, Username: "alice", TotalPoints: 5},
+		{UserID: 2, Username: "bob", TotalPoints: 3},
+		{UserID: 3, Username: "charlie", TotalPoints: 3},
+	}
+	// Tied users listed in a different order aren't reported as movement
+	after := []models.LeaderboardEntry{
+		{UserID: 1, Username: "alice", TotalPoints: 5},
+		{UserID: 3, Username: "charlie", TotalPoints: 3},
+		{UserID: 2, Username: "bob", TotalPoints: 3},
+	}
+	assert.Empty(t, Diff(1, before, after))
+
+	after = []models.LeaderboardEntry{
+		{UserID: 1, Username: "alice", TotalPoints: 5},
+		{UserID: 3, Username: "charlie", TotalPoints: 4},
+		{UserID: 2, Username: "bob", TotalPoints: 3},
+	}
+	assert.Equal(t, []LeaderboardChange{
+		{LeagueID: 1, UserID: 3, Username: "charlie", PointsBefore: 3, PointsAfter: 4, RankBefore: 2, RankAfter: 2},
+		{LeagueID: 1, UserID: 2, Username: "bob", PointsBefore: 3, PointsAfter: 3, RankBefore: 2, RankAfter: 3},
+	}, Diff(1, before, after))
+}
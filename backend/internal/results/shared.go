@@ -0,0 +1,108 @@
+package results
+
+import (
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/validation"
+	"github.com/ckinger23/mountaintop/internal/weeks"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LeagueOutcome is what a master schedule result changed in one league's copy of the game
+type LeagueOutcome struct {
+	GameID   uint `json:"game_id"`
+	LeagueID uint `json:"league_id"`
+	Outcome
+}
+
+// RecordScheduled saves a result on a master schedule game and records it on every league game selected from it
+// League games the league has postponed or cancelled on its own are skipped. A league whose week is
+// finished blocks the whole result with ErrWeekFinished unless sub.Override is set.
+// This function should be called within a transaction
+func RecordScheduled(tx *gorm.DB, sg *models.ScheduledGame, sub Submission, actor weeks.Actor) ([]LeagueOutcome, error) {
+	if sg.Status == models.GameStatusPostponed || sg.Status == models.GameStatusCancelled {
+		return nil, ErrGameNotPlayed
+	}
+
+	homeScore, awayScore := sub.HomeScore, sub.AwayScore
+	sg.HomeScore = &homeScore
+	sg.AwayScore = &awayScore
+	sg.IsFinal = sub.IsFinal
+	sg.Status = models.GameStatusInProgress
+	if sub.IsFinal {
+		sg.Status = models.GameStatusFinal
+	}
+	if err := tx.Omit(clause.Associations).Save(sg).Error; err != nil {
+		return nil, err
+	}
+
+	var games []models.Game
+	if err := tx.Preload("Week.Season").Where("scheduled_game_id = ?", sg.ID).Find(&games).Error; err != nil {
+		return nil, err
+	}
+
+	outcomes := []LeagueOutcome{}
+	for i := range games {
+		if status := statusOf(&games[i]); status == models.GameStatusPostponed || status == models.GameStatusCancelled {
+			continue
+		}
+
+		outcome, err := Record(tx, &games[i], sub, actor)
+		if err != nil {
+			return nil, err
+		}
+		outcomes = append(outcomes, LeagueOutcome{
+			GameID:   games[i].ID,
+			LeagueID: games[i].Week.Season.LeagueID,
+			Outcome:  *outcome,
+		})
+	}
+
+	return outcomes, nil
+}
+
+// LeagueStatus is the status a master schedule change left one league's copy of the game in
+type LeagueStatus struct {
+	GameID   uint   `json:"game_id"`
+	LeagueID uint   `json:"league_id"`
+	Status   string `json:"status"`
+}
+
+// SetScheduledStatus moves a master schedule game to a new status and every league game selected from it with it
+// Cancelling voids the picks in every league. League games already in the new status are left alone.
+// Returns a *validation.ValidationError for transitions that aren't allowed. This function should be called within a transaction
+func SetScheduledStatus(tx *gorm.DB, sg *models.ScheduledGame, newStatus string) ([]LeagueStatus, error) {
+	current := sg.Status
+	if current == "" {
+		current = models.GameStatusScheduled
+	}
+	if valErr := validation.ValidateGameStatusTransition(current, newStatus); valErr != nil {
+		return nil, valErr
+	}
+
+	if err := tx.Model(&models.ScheduledGame{}).Where("id = ?", sg.ID).Update("status", newStatus).Error; err != nil {
+		return nil, err
+	}
+	sg.Status = newStatus
+
+	var games []models.Game
+	if err := tx.Preload("Week.Season").Where("scheduled_game_id = ?", sg.ID).Find(&games).Error; err != nil {
+		return nil, err
+	}
+
+	statuses := []LeagueStatus{}
+	for i := range games {
+		if statusOf(&games[i]) != newStatus {
+			if err := SetStatus(tx, &games[i], newStatus); err != nil {
+				return nil, err
+			}
+		}
+		statuses = append(statuses, LeagueStatus{
+			GameID:   games[i].ID,
+			LeagueID: games[i].Week.Season.LeagueID,
+			Status:   newStatus,
+		})
+	}
+
+	return statuses, nil
+}
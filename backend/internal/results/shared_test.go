@@ -0,0 +1,128 @@
+package results
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/validation"
+	"github.com/ckinger23/mountaintop/internal/weeks"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// seedSharedGame puts one master schedule game on the slate of a league per week status, each with a home pick
+func seedSharedGame(t *testing.T, db *gorm.DB, weekStatuses ...string) (models.ScheduledGame, []models.Game) {
+	home := models.Team{Name: "Home", Abbreviation: "HOM"}
+	away := models.Team{Name: "Away", Abbreviation: "AWY"}
+	db.Create(&home)
+	db.Create(&away)
+
+	sg := models.ScheduledGame{Year: 2025, WeekNumber: 1, HomeTeamID: home.ID, AwayTeamID: away.ID, HomeSpread: -3.5, Total: 40.5}
+	if err := db.Create(&sg).Error; err != nil {
+		t.Fatalf("Failed to create scheduled game: %v", err)
+	}
+
+	var games []models.Game
+	for i, status := range weekStatuses {
+		owner := models.User{Username: fmt.Sprintf("owner%d", i), Email: fmt.Sprintf("owner%d@example.com", i), PasswordHash: "hash"}
+		db.Create(&owner)
+		league := models.League{Name: "League", Code: fmt.Sprintf("SHR-%d", i), OwnerID: owner.ID, IsActive: true}
+		db.Create(&league)
+		season := models.Season{LeagueID: league.ID, Year: 2025}
+		db.Create(&season)
+		week := models.Week{SeasonID: season.ID, WeekNumber: 1, Name: "Week 1", Status: status}
+		db.Create(&week)
+
+		game := models.Game{WeekID: week.ID, HomeTeamID: home.ID, AwayTeamID: away.ID, HomeSpread: -3.5, Total: 40.5, ScheduledGameID: &sg.ID}
+		if err := db.Create(&game).Error; err != nil {
+			t.Fatalf("Failed to create game: %v", err)
+		}
+		db.Create(&models.Pick{LeagueID: league.ID, UserID: owner.ID, GameID: game.ID, PickedTeamID: home.ID, PickedOverUnder: "over"})
+		games = append(games, game)
+	}
+
+	return sg, games
+}
+
+func TestRecordScheduled(t *testing.T) {
+	db := setupTestDB(t)
+	sg, games := seedSharedGame(t, db, "scoring", "scoring", "scoring")
+
+	// The third league cancelled its copy of the game on its own
+	db.Model(&models.Game{}).Where("id = ?", games[2].ID).Update("status", models.GameStatusCancelled)
+
+	var outcomes []LeagueOutcome
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		outcomes, err = RecordScheduled(tx, &sg, Submission{HomeScore: 28, AwayScore: 14, IsFinal: true}, weeks.UserActor(1))
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Len(t, outcomes, 2)
+
+	var updated models.ScheduledGame
+	db.First(&updated, sg.ID)
+	assert.True(t, updated.IsFinal)
+	assert.Equal(t, models.GameStatusFinal, updated.Status)
+
+	// One result scored every league that kept the game
+	for i, want := range []models.PickOutcome{models.PickOutcomeWin, models.PickOutcomeWin, models.PickOutcomePending} {
+		var pick models.Pick
+		db.Where("game_id = ?", games[i].ID).First(&pick)
+		assert.Equal(t, want, pick.SpreadOutcome, "league %d", i)
+	}
+}
+
+func TestRecordScheduled_FinishedWeek(t *testing.T) {
+	db := setupTestDB(t)
+	sg, games := seedSharedGame(t, db, "scoring", "finished")
+
+	// A finished week in any league blocks the result for every league
+	err := db.Transaction(func(tx *gorm.DB) error {
+		_, err := RecordScheduled(tx, &sg, Submission{HomeScore: 28, AwayScore: 14, IsFinal: true}, weeks.UserActor(1))
+		return err
+	})
+	assert.ErrorIs(t, err, ErrWeekFinished)
+
+	var game models.Game
+	db.First(&game, games[0].ID)
+	assert.False(t, game.IsFinal)
+	var updated models.ScheduledGame
+	db.First(&updated, sg.ID)
+	assert.False(t, updated.IsFinal)
+}
+
+func TestSetScheduledStatus(t *testing.T) {
+	db := setupTestDB(t)
+	sg, games := seedSharedGame(t, db, "picking", "picking")
+
+	var statuses []LeagueStatus
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		statuses, err = SetScheduledStatus(tx, &sg, models.GameStatusCancelled)
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 2)
+
+	var updated models.ScheduledGame
+	db.First(&updated, sg.ID)
+	assert.Equal(t, models.GameStatusCancelled, updated.Status)
+
+	// Every league's copy is cancelled and its picks voided
+	for i := range games {
+		var game models.Game
+		db.First(&game, games[i].ID)
+		assert.Equal(t, models.GameStatusCancelled, game.Status, "league %d", i)
+
+		var pick models.Pick
+		db.Where("game_id = ?", games[i].ID).First(&pick)
+		assert.Equal(t, models.PickOutcomeVoid, pick.SpreadOutcome, "league %d", i)
+	}
+
+	// Cancelled games can't be reopened
+	var valErr *validation.ValidationError
+	_, err = SetScheduledStatus(db, &sg, models.GameStatusScheduled)
+	assert.ErrorAs(t, err, &valErr)
+}
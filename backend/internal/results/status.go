@@ -0,0 +1,160 @@
+package results
+
+import (
+	"time"
+
+	"github.com/ckinger23/mountaintop/internal/leaderboard"
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/scoring"
+	"github.com/ckinger23/mountaintop/internal/validation"
+	"gorm.io/gorm"
+)
+
+// SetStatus moves a game to a new status
+// Cancelling a game voids every pick on it. Final is not set here; it comes from Record.
+// Returns a *validation.ValidationError for transitions that aren't allowed. This function should be called within a transaction
+func SetStatus(tx *gorm.DB, game *models.Game, newStatus string) error {
+	if valErr := validation.ValidateGameStatusTransition(statusOf(game), newStatus); valErr != nil {
+		return valErr
+	}
+
+	if err := tx.Model(&models.Game{}).Where("id = ?", game.ID).Update("status", newStatus).Error; err != nil {
+		return err
+	}
+	game.Status = newStatus
+
+	if newStatus == models.GameStatusCancelled {
+		return voidPicks(tx, game.ID)
+	}
+	return nil
+}
+
+// Postpone marks a game postponed and moves it to another week, at a new kickoff, when target is given
+// A moved game is scheduled again. Its picks are kept or voided per the league's PostponedPicks setting;
+// kept confidence picks whose value is taken in the new week get the lowest free one.
+// survivor picks belong to the week they were made in, so they're always voided.
+// game must have its Week.Season loaded. This function should be called within a transaction
+func Postpone(tx *gorm.DB, game *models.Game, target *models.Week, gameTime time.Time) error {
+	if statusOf(game) != models.GameStatusPostponed {
+		if err := SetStatus(tx, game, models.GameStatusPostponed); err != nil {
+			return err
+		}
+	}
+
+	if target == nil {
+		return nil
+	}
+
+	settings, err := scoring.LoadSettings(tx, game.Week.Season.LeagueID)
+	if err != nil {
+		return err
+	}
+
+	fromWeekID := game.WeekID
+	if err := tx.Model(&models.Game{}).Where("id = ?", game.ID).Updates(map[string]interface{}{
+		"week_id":   target.ID,
+		"game_time": gameTime,
+		"status":    models.GameStatusScheduled,
+	}).Error; err != nil {
+		return err
+	}
+	game.WeekID = target.ID
+	game.Week = *target
+	game.GameTime = gameTime
+	game.Status = models.GameStatusScheduled
+
+	if err := tx.Model(&models.SurvivorPick{}).Where("game_id = ?", game.ID).
+		Update("outcome", models.PickOutcomeVoid).Error; err != nil {
+		return err
+	}
+
+	if settings.PostponedPicks == models.PostponedPicksVoid {
+		if err := tx.Model(&models.Pick{}).Where("game_id = ?", game.ID).Updates(voidedPick).Error; err != nil {
+			return err
+		}
+	} else if settings.Format == models.LeagueFormatConfidence {
+		if err := resolveConfidence(tx, game); err != nil {
+			return err
+		}
+	}
+
+	// Kept picks now count toward the week the game moved to
+	if err := leaderboard.RefreshWeek(tx, fromWeekID); err != nil {
+		return err
+	}
+	return leaderboard.RefreshWeek(tx, target.ID)
+}
+
+// resolveConfidence keeps confidence values unique after a game with kept picks moves to another week
+// A pick whose value is already used on one of the member's picks in the new week takes the lowest value they
+// haven't used there
+func resolveConfidence(tx *gorm.DB, game *models.Game) error {
+	var picks []models.Pick
+	if err := tx.Where("game_id = ?", game.ID).Find(&picks).Error; err != nil {
+		return err
+	}
+
+	for _, pick := range picks {
+		var used []int
+		if err := tx.Model(&models.Pick{}).
+			Joins("JOIN games ON games.id = picks.game_id").
+			Where("picks.league_id = ? AND picks.user_id = ? AND games.week_id = ? AND picks.game_id <> ?", pick.LeagueID, pick.UserID, game.WeekID, game.ID).
+			Pluck("picks.confidence", &used).Error; err != nil {
+			return err
+		}
+
+		taken := make(map[int]bool, len(used))
+		for _, value := range used {
+			taken[value] = true
+		}
+		if !taken[pick.Confidence] {
+			continue
+		}
+
+		value := 1
+		for taken[value] {
+			value++
+		}
+		if err := tx.Model(&models.Pick{}).Where("id = ?", pick.ID).Update("confidence", value).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// voidedPick is the update applied to picks that no longer count
+var voidedPick = map[string]interface{}{
+	"spread_outcome":     models.PickOutcomeVoid,
+	"over_under_outcome": models.PickOutcomeVoid,
+	"points_earned":      0,
+}
+
+// voidPicks voids every pick, survivor pick and bracket pick on a game
+func voidPicks(tx *gorm.DB, gameID uint) error {
+	if err := tx.Model(&models.Pick{}).Where("game_id = ?", gameID).Updates(voidedPick).Error; err != nil {
+		return err
+	}
+	if err := leaderboard.RefreshGame(tx, gameID); err != nil {
+		return err
+	}
+
+	if err := tx.Model(&models.SurvivorPick{}).Where("game_id = ?", gameID).
+		Update("outcome", models.PickOutcomeVoid).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(&models.BracketPick{}).
+		Where("slot_id IN (?)", tx.Model(&models.BracketSlot{}).Select("id").Where("game_id = ?", gameID)).
+		Updates(map[string]interface{}{
+			"outcome":       models.PickOutcomeVoid,
+			"points_earned": 0,
+		}).Error
+}
+
+// statusOf returns a game's status, treating games saved before statuses existed as scheduled
+func statusOf(game *models.Game) string {
+	if game.Status == "" {
+		return models.GameStatusScheduled
+	}
+	return game.Status
+}
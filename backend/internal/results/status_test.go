@@ -0,0 +1,127 @@
+package results
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/validation"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestSetStatus_Cancelled(t *testing.T) {
+	db := setupTestDB(t)
+	f := seedGame(t, db, "picking")
+	db.Create(&models.SurvivorPick{LeagueID: 1, UserID: f.alice.ID, SeasonID: 1, WeekID: f.week.ID, GameID: f.game.ID, TeamID: f.game.HomeTeamID})
+
+	assert.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+		return SetStatus(tx, &f.game, models.GameStatusCancelled)
+	}))
+
+	var picks []models.Pick
+	db.Find(&picks)
+	for _, pick := range picks {
+		assert.Equal(t, models.PickOutcomeVoid, pick.SpreadOutcome)
+		assert.Equal(t, models.PickOutcomeVoid, pick.OverUnderOutcome)
+	}
+
+	var survivorPick models.SurvivorPick
+	db.First(&survivorPick)
+	assert.Equal(t, models.PickOutcomeVoid, survivorPick.Outcome)
+
+	// Cancelled games can't be reopened or given a result
+	var valErr *validation.ValidationError
+	assert.ErrorAs(t, SetStatus(db, &f.game, models.GameStatusScheduled), &valErr)
+	_, err := record(db, &f.game, Submission{HomeScore: 28, AwayScore: 17, IsFinal: true})
+	assert.ErrorIs(t, err, ErrGameNotPlayed)
+}
+
+func TestPostpone(t *testing.T) {
+	tests := []struct {
+		name           string
+		postponedPicks string
+		wantOutcome    models.PickOutcome
+		wantPoints     int
+	}{
+		{"picks are kept", models.PostponedPicksKeep, models.PickOutcomeWin, 2},
+		{"picks are voided", models.PostponedPicksVoid, models.PickOutcomeVoid, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := setupTestDB(t)
+			f := seedGame(t, db, "picking")
+
+			settings := models.LeagueSettings{LeagueID: 1, Format: models.LeagueFormatStandard, SpreadPoints: 1, OverUnderPoints: 1, OverUnderEnabled: true, PostponedPicks: tt.postponedPicks}
+			db.Create(&settings)
+
+			next := models.Week{SeasonID: f.week.SeasonID, WeekNumber: 2, Name: "Week 2", Status: "creating"}
+			db.Create(&next)
+			kickoff := time.Now().Add(7 * 24 * time.Hour)
+
+			db.Preload("Week.Season").First(&f.game, f.game.ID)
+			assert.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+				return Postpone(tx, &f.game, &next, kickoff)
+			}))
+
+			var game models.Game
+			db.Preload("Week").First(&game, f.game.ID)
+			assert.Equal(t, next.ID, game.WeekID)
+			assert.Equal(t, models.GameStatusScheduled, game.Status)
+
+			// Once played, only picks that weren't voided are graded
+			_, err := record(db, &game, Submission{HomeScore: 28, AwayScore: 17, IsFinal: true})
+			assert.NoError(t, err)
+
+			var pick models.Pick
+			db.Where("user_id = ?", f.alice.ID).First(&pick)
+			assert.Equal(t, tt.wantOutcome, pick.SpreadOutcome)
+			assert.Equal(t, tt.wantPoints, pick.PointsEarned)
+		})
+	}
+}
+
+func TestPostpone_ConfidenceClash(t *testing.T) {
+	db := setupTestDB(t)
+	f := seedGame(t, db, "picking")
+	db.Create(&models.LeagueSettings{LeagueID: 1, Format: models.LeagueFormatConfidence, SpreadPoints: 1, OverUnderPoints: 1, OverUnderEnabled: true, PostponedPicks: models.PostponedPicksKeep})
+	db.Model(&models.Pick{}).Where("game_id = ?", f.game.ID).Update("confidence", 1)
+
+	// Alice already put 1 on a game in the week the postponed game moves to; bob hasn't picked there
+	next := models.Week{SeasonID: f.week.SeasonID, WeekNumber: 2, Name: "Week 2", Status: "picking"}
+	db.Create(&next)
+	nextGame := models.Game{WeekID: next.ID, HomeTeamID: f.game.HomeTeamID, AwayTeamID: f.game.AwayTeamID}
+	db.Create(&nextGame)
+	db.Create(&models.Pick{LeagueID: 1, UserID: f.alice.ID, GameID: nextGame.ID, PickedTeamID: nextGame.HomeTeamID, PickedOverUnder: "over", Confidence: 1})
+
+	db.Preload("Week.Season").First(&f.game, f.game.ID)
+	assert.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+		return Postpone(tx, &f.game, &next, time.Now().Add(7*24*time.Hour))
+	}))
+
+	// Alice's moved pick takes the lowest value she hasn't used; bob's keeps its value
+	var alicePick, bobPick models.Pick
+	db.Where("game_id = ? AND user_id = ?", f.game.ID, f.alice.ID).First(&alicePick)
+	db.Where("game_id = ? AND user_id = ?", f.game.ID, f.bob.ID).First(&bobPick)
+	assert.Equal(t, 2, alicePick.Confidence)
+	assert.Equal(t, 1, bobPick.Confidence)
+}
+
+func TestPostpone_InPlace(t *testing.T) {
+	db := setupTestDB(t)
+	f := seedGame(t, db, "picking")
+
+	// Without a target week the game stays put and waits to be rescheduled
+	assert.NoError(t, Postpone(db, &f.game, nil, time.Time{}))
+	assert.Equal(t, models.GameStatusPostponed, f.game.Status)
+	assert.Equal(t, f.week.ID, f.game.WeekID)
+
+	_, err := record(db, &f.game, Submission{HomeScore: 28, AwayScore: 17, IsFinal: true})
+	assert.ErrorIs(t, err, ErrGameNotPlayed)
+
+	assert.NoError(t, SetStatus(db, &f.game, models.GameStatusScheduled))
+	_, err = record(db, &f.game, Submission{HomeScore: 28, AwayScore: 17, IsFinal: true})
+	assert.NoError(t, err)
+	assert.Equal(t, models.GameStatusFinal, f.game.Status)
+}
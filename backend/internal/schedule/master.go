@@ -0,0 +1,72 @@
+package schedule
+
+import (
+	"fmt"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/odds"
+	"github.com/ckinger23/mountaintop/internal/validation"
+	"github.com/ckinger23/mountaintop/internal/weeks"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Select adds master schedule games to a week's slate as league games linked back to them
+// Each league game starts with the master schedule's suggested line, which the league can then edit.
+// Returns a *validation.ValidationError for games that can't be selected. week must have its Season loaded.
+// This function should be called within a transaction
+func Select(tx *gorm.DB, week *models.Week, scheduled []models.ScheduledGame, actor weeks.Actor) ([]models.Game, error) {
+	var selected []uint
+	if err := tx.Model(&models.Game{}).Where("week_id = ? AND scheduled_game_id IS NOT NULL", week.ID).Pluck("scheduled_game_id", &selected).Error; err != nil {
+		return nil, err
+	}
+	onSlate := make(map[uint]bool)
+	for _, id := range selected {
+		onSlate[id] = true
+	}
+
+	details := make(map[string]string)
+	for _, sg := range scheduled {
+		key := fmt.Sprintf("scheduled_game_ids[%d]", sg.ID)
+		switch {
+		case onSlate[sg.ID]:
+			details[key] = "Game is already on this week's slate"
+		case sg.Year != week.Season.Year:
+			details[key] = fmt.Sprintf("Game is from the %d schedule, not %d", sg.Year, week.Season.Year)
+		case sg.IsFinal || sg.Status == models.GameStatusCancelled:
+			details[key] = "Game is already final or cancelled"
+		}
+		onSlate[sg.ID] = true
+	}
+	if len(details) > 0 {
+		return nil, validation.NewValidationError("Validation failed", details)
+	}
+
+	games := make([]models.Game, 0, len(scheduled))
+	for _, sg := range scheduled {
+		scheduledGameID := sg.ID
+		game := models.Game{
+			WeekID:          week.ID,
+			HomeTeamID:      sg.HomeTeamID,
+			AwayTeamID:      sg.AwayTeamID,
+			GameTime:        sg.GameTime,
+			HomeSpread:      sg.HomeSpread,
+			Total:           sg.Total,
+			Status:          sg.Status,
+			ExternalID:      sg.ExternalID,
+			ScheduledGameID: &scheduledGameID,
+		}
+		if game.Status == "" {
+			game.Status = models.GameStatusScheduled
+		}
+		if err := tx.Omit(clause.Associations).Create(&game).Error; err != nil {
+			return nil, err
+		}
+		if err := odds.RecordLine(tx, game.ID, game.HomeSpread, game.Total, actor); err != nil {
+			return nil, err
+		}
+		games = append(games, game)
+	}
+
+	return games, nil
+}
@@ -0,0 +1,52 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/validation"
+	"github.com/ckinger23/mountaintop/internal/weeks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelect(t *testing.T) {
+	db, _ := setupTestDB(t)
+
+	season := models.Season{LeagueID: 1, Year: 2025}
+	db.Create(&season)
+	var week models.Week
+	db.Where("week_number = ?", 1).First(&week)
+	week.Season = season
+
+	kickoff := time.Date(2025, 9, 6, 19, 0, 0, 0, time.UTC)
+	scheduled := []models.ScheduledGame{
+		{Year: 2025, WeekNumber: 1, HomeTeamID: 1, AwayTeamID: 2, GameTime: kickoff, HomeSpread: -7, Total: 50.5, ExternalID: "401"},
+		{Year: 2024, WeekNumber: 1, HomeTeamID: 3, AwayTeamID: 4, GameTime: kickoff, HomeSpread: 3, Total: 55},
+	}
+	db.Create(&scheduled)
+
+	games, err := Select(db, &week, scheduled[:1], weeks.UserActor(1))
+	assert.NoError(t, err)
+	if assert.Len(t, games, 1) {
+		assert.Equal(t, scheduled[0].ID, *games[0].ScheduledGameID)
+		assert.Equal(t, week.ID, games[0].WeekID)
+		assert.Equal(t, -7.0, games[0].HomeSpread)
+		assert.Equal(t, "401", games[0].ExternalID)
+	}
+
+	var lines int64
+	db.Model(&models.GameLine{}).Count(&lines)
+	assert.Equal(t, int64(1), lines)
+
+	// Games already on the slate or from another year are rejected, and nothing is added
+	_, err = Select(db, &week, scheduled, weeks.UserActor(1))
+	var valErr *validation.ValidationError
+	if assert.ErrorAs(t, err, &valErr) {
+		assert.Len(t, valErr.Details, 2)
+	}
+
+	var count int64
+	db.Model(&models.Game{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
@@ -0,0 +1,268 @@
+package schedule
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/odds"
+	"github.com/ckinger23/mountaintop/internal/teammatch"
+	"github.com/ckinger23/mountaintop/internal/validation"
+	"github.com/ckinger23/mountaintop/internal/weeks"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Schedule formats
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// Row is one game in an imported schedule
+type Row struct {
+	Week       int     `json:"week"`      // Week number within the season; optional when importing into one week
+	HomeTeam   string  `json:"home_team"` // Team name, abbreviation or alias
+	AwayTeam   string  `json:"away_team"` // Team name, abbreviation or alias
+	Kickoff    string  `json:"kickoff"`   // RFC3339
+	HomeSpread float64 `json:"home_spread"`
+	Total      float64 `json:"total"`
+	ExternalID string  `json:"external_id"`
+
+	// Columns that couldn't be parsed, reported with the row's other errors
+	parseErrors map[string]string
+}
+
+// RowError lists the problems with one row, keyed by column
+type RowError struct {
+	Row     int               `json:"row"` // 1-based, not counting a CSV header
+	Details map[string]string `json:"details"`
+}
+
+// Parse reads a schedule in CSV or JSON format
+// CSV needs a header row with home_team, away_team, kickoff, home_spread and total columns,
+// plus optional week and external_id columns. JSON is a list of Row
+func Parse(format string, r io.Reader) ([]Row, error) {
+	switch format {
+	case FormatJSON:
+		var rows []Row
+		if err := json.NewDecoder(r).Decode(&rows); err != nil {
+			return nil, fmt.Errorf("invalid schedule: %w", err)
+		}
+		return rows, nil
+	case FormatCSV:
+		return parseCSV(r)
+	default:
+		return nil, fmt.Errorf("unsupported schedule format %q (expected csv or json)", format)
+	}
+}
+
+// parseCSV reads rows from CSV with a header row
+func parseCSV(r io.Reader) ([]Row, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"home_team", "away_team", "kickoff", "home_spread", "total"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("invalid schedule: missing %s column", required)
+		}
+	}
+
+	var rows []Row
+	for _, record := range records[1:] {
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		row := Row{
+			HomeTeam:    field("home_team"),
+			AwayTeam:    field("away_team"),
+			Kickoff:     field("kickoff"),
+			ExternalID:  field("external_id"),
+			parseErrors: make(map[string]string),
+		}
+		if value := field("week"); value != "" {
+			if row.Week, err = strconv.Atoi(value); err != nil {
+				row.parseErrors["week"] = "Week must be a whole number"
+			}
+		}
+		if row.HomeSpread, err = strconv.ParseFloat(field("home_spread"), 64); err != nil {
+			row.parseErrors["home_spread"] = "Home spread must be a number"
+		}
+		if row.Total, err = strconv.ParseFloat(field("total"), 64); err != nil {
+			row.parseErrors["total"] = "Total must be a number"
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// columnFor maps validation.ValidateCreateGame's fields to schedule columns
+var columnFor = map[string]string{
+	"week_id":      "week",
+	"home_team_id": "home_team",
+	"away_team_id": "away_team",
+	"game_time":    "kickoff",
+}
+
+// Plan resolves and validates every row of a schedule without saving anything
+// Rows are placed in the season's weeks by number. When into is set every row goes into that week,
+// and rows that give a week number must name it. Games are returned in row order; if any row has
+// problems, every failing row is reported and no games are returned
+func Plan(db *gorm.DB, seasonID uint, into *models.Week, rows []Row, resolver *teammatch.Resolver) ([]models.Game, []RowError, error) {
+	var seasonWeeks []models.Week
+	if err := db.Where("season_id = ?", seasonID).Find(&seasonWeeks).Error; err != nil {
+		return nil, nil, err
+	}
+	weekByNumber := make(map[int]*models.Week)
+	weekIDs := make([]uint, 0, len(seasonWeeks))
+	for i := range seasonWeeks {
+		weekByNumber[seasonWeeks[i].WeekNumber] = &seasonWeeks[i]
+		weekIDs = append(weekIDs, seasonWeeks[i].ID)
+	}
+
+	// Games already on the schedule can't be imported again
+	var existing []models.Game
+	if len(weekIDs) > 0 {
+		if err := db.Where("week_id IN ?", weekIDs).Find(&existing).Error; err != nil {
+			return nil, nil, err
+		}
+	}
+	scheduled := make(map[string]int) // matchup -> row that scheduled it, 0 if already in the database
+	for _, game := range existing {
+		scheduled[matchup(game.WeekID, game.HomeTeamID, game.AwayTeamID)] = 0
+	}
+
+	games := make([]models.Game, 0, len(rows))
+	rowErrors := []RowError{}
+
+	for i, row := range rows {
+		details := make(map[string]string)
+		for column, msg := range row.parseErrors {
+			details[column] = msg
+		}
+
+		// Resolve the week
+		var week *models.Week
+		switch {
+		case into != nil && row.Week != 0 && row.Week != into.WeekNumber:
+			details["week"] = fmt.Sprintf("Rows must be for week %d", into.WeekNumber)
+		case into != nil:
+			week = into
+		case row.Week == 0:
+			if _, ok := details["week"]; !ok {
+				details["week"] = "Week is required"
+			}
+		default:
+			if week = weekByNumber[row.Week]; week == nil {
+				details["week"] = fmt.Sprintf("Week %d does not exist in this season", row.Week)
+			}
+		}
+
+		// Resolve the teams
+		var game models.Game
+		if home, ok := resolver.Resolve(row.HomeTeam); ok {
+			game.HomeTeamID = home.ID
+			game.HomeTeam = *home
+		} else if row.HomeTeam != "" {
+			details["home_team"] = "Unknown team: " + row.HomeTeam
+		}
+		if away, ok := resolver.Resolve(row.AwayTeam); ok {
+			game.AwayTeamID = away.ID
+			game.AwayTeam = *away
+		} else if row.AwayTeam != "" {
+			details["away_team"] = "Unknown team: " + row.AwayTeam
+		}
+
+		gameTime, valErr := validation.ValidateGameTime(row.Kickoff)
+		if valErr != nil {
+			mergeDetails(details, valErr.Details)
+		}
+
+		if week != nil {
+			game.WeekID = week.ID
+		}
+		if valErr := validation.ValidateCreateGame(game.WeekID, game.HomeTeamID, game.AwayTeamID, gameTime, row.HomeSpread, row.Total); valErr != nil {
+			mergeDetails(details, valErr.Details)
+		}
+
+		if game.WeekID != 0 && game.HomeTeamID != 0 && game.AwayTeamID != 0 {
+			key := matchup(game.WeekID, game.HomeTeamID, game.AwayTeamID)
+			if prev, ok := scheduled[key]; ok {
+				if prev == 0 {
+					details["game"] = "This game is already scheduled"
+				} else {
+					details["game"] = fmt.Sprintf("Duplicate of row %d", prev)
+				}
+			} else {
+				scheduled[key] = i + 1
+			}
+		}
+
+		if len(details) > 0 {
+			rowErrors = append(rowErrors, RowError{Row: i + 1, Details: details})
+			continue
+		}
+
+		game.GameTime = gameTime
+		game.HomeSpread = row.HomeSpread
+		game.Total = row.Total
+		game.ExternalID = row.ExternalID
+		games = append(games, game)
+	}
+
+	if len(rowErrors) > 0 {
+		return nil, rowErrors, nil
+	}
+	return games, rowErrors, nil
+}
+
+// Insert creates planned games and starts each one's line history
+// This function should be called within a transaction
+func Insert(tx *gorm.DB, games []models.Game, actor weeks.Actor) error {
+	for i := range games {
+		if err := tx.Omit(clause.Associations).Create(&games[i]).Error; err != nil {
+			return err
+		}
+		if err := odds.RecordLine(tx, games[i].ID, games[i].HomeSpread, games[i].Total, actor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeDetails adds validation details under their schedule column names, keeping the first problem per column
+func mergeDetails(details, from map[string]string) {
+	for field, msg := range from {
+		column, ok := columnFor[field]
+		if !ok {
+			column = field
+		}
+		if _, exists := details[column]; !exists {
+			details[column] = msg
+		}
+	}
+}
+
+// matchup identifies a game by week and teams
+func matchup(weekID, homeTeamID, awayTeamID uint) string {
+	return fmt.Sprintf("%d:%d:%d", weekID, homeTeamID, awayTeamID)
+}
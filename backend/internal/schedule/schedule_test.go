@@ -0,0 +1,161 @@
+package schedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/teammatch"
+	"github.com/ckinger23/mountaintop/internal/weeks"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupTestDB creates an in-memory SQLite database with two weeks and a few teams
+func setupTestDB(t *testing.T) (*gorm.DB, *teammatch.Resolver) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+
+	err = db.AutoMigrate(&models.Team{}, &models.TeamAlias{}, &models.Season{}, &models.Week{}, &models.ScheduledGame{}, &models.Game{}, &models.GameLine{})
+	if err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	db.Create(&[]models.Team{
+		{Name: "Alabama", Abbreviation: "ALA"},
+		{Name: "Auburn", Abbreviation: "AUB"},
+		{Name: "Georgia", Abbreviation: "UGA"},
+		{Name: "Ole Miss", Abbreviation: "MISS", Aliases: []models.TeamAlias{{Alias: "Mississippi"}}},
+	})
+	db.Create(&[]models.Week{
+		{SeasonID: 1, WeekNumber: 1, Name: "Week 1", Status: "creating"},
+		{SeasonID: 1, WeekNumber: 2, Name: "Week 2", Status: "creating"},
+	})
+
+	resolver, err := teammatch.NewResolver(db)
+	if err != nil {
+		t.Fatalf("Failed to load teams: %v", err)
+	}
+	return db, resolver
+}
+
+func TestParse(t *testing.T) {
+	csvRows, err := Parse(FormatCSV, strings.NewReader(
+		"week,home_team,away_team,kickoff,home_spread,total\n"+
+			"1,ALA,AUB,2030-09-06T19:00:00Z,-7,50.5\n"+
+			"x,UGA,MISS,2030-09-06T19:00:00Z,even,48\n"))
+	assert.NoError(t, err)
+	if assert.Len(t, csvRows, 2) {
+		assert.Equal(t, 1, csvRows[0].Week)
+		assert.Equal(t, -7.0, csvRows[0].HomeSpread)
+		assert.Empty(t, csvRows[0].parseErrors)
+		assert.Equal(t, map[string]string{"week": "Week must be a whole number", "home_spread": "Home spread must be a number"}, csvRows[1].parseErrors)
+	}
+
+	jsonRows, err := Parse(FormatJSON, strings.NewReader(`[{"week": 2, "home_team": "ALA", "away_team": "AUB", "kickoff": "2030-09-06T19:00:00Z", "home_spread": -7, "total": 50.5}]`))
+	assert.NoError(t, err)
+	assert.Equal(t, []Row{{Week: 2, HomeTeam: "ALA", AwayTeam: "AUB", Kickoff: "2030-09-06T19:00:00Z", HomeSpread: -7, Total: 50.5}}, jsonRows)
+
+	_, err = Parse(FormatCSV, strings.NewReader("home_team,away_team\nALA,AUB\n"))
+	assert.Error(t, err)
+	_, err = Parse("xml", strings.NewReader(""))
+	assert.Error(t, err)
+}
+
+func TestPlanAndInsert(t *testing.T) {
+	db, resolver := setupTestDB(t)
+	kickoff := time.Now().Add(72 * time.Hour).UTC().Format(time.RFC3339)
+
+	rows := []Row{
+		{Week: 1, HomeTeam: "Alabama", AwayTeam: "auburn", Kickoff: kickoff, HomeSpread: -7, Total: 50.5},
+		{Week: 2, HomeTeam: "Mississippi", AwayTeam: "UGA", Kickoff: kickoff, HomeSpread: 3, Total: 55},
+	}
+
+	games, rowErrors, err := Plan(db, 1, nil, rows, resolver)
+	assert.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	if assert.Len(t, games, 2) {
+		assert.Equal(t, "Ole Miss", games[1].HomeTeam.Name)
+	}
+
+	assert.NoError(t, Insert(db, games, weeks.CLIActor("test")))
+
+	var count int64
+	db.Model(&models.Game{}).Count(&count)
+	assert.Equal(t, int64(2), count)
+	db.Model(&models.GameLine{}).Count(&count)
+	assert.Equal(t, int64(2), count)
+
+	// Importing the same games again is rejected
+	_, rowErrors, err = Plan(db, 1, nil, rows[:1], resolver)
+	assert.NoError(t, err)
+	if assert.Len(t, rowErrors, 1) {
+		assert.Equal(t, "This game is already scheduled", rowErrors[0].Details["game"])
+	}
+}
+
+func TestPlan_RowErrors(t *testing.T) {
+	db, resolver := setupTestDB(t)
+	kickoff := time.Now().Add(72 * time.Hour).UTC().Format(time.RFC3339)
+
+	var week2 models.Week
+	db.Where("week_number = ?", 2).First(&week2)
+
+	tests := []struct {
+		name string
+		into *models.Week
+		row  Row
+		want map[string]string
+	}{
+		{
+			name: "unknown team and week",
+			row:  Row{Week: 9, HomeTeam: "Vanderbilt", AwayTeam: "AUB", Kickoff: kickoff, HomeSpread: -7, Total: 50},
+			want: map[string]string{"week": "Week 9 does not exist in this season", "home_team": "Unknown team: Vanderbilt"},
+		},
+		{
+			name: "missing week",
+			row:  Row{HomeTeam: "ALA", AwayTeam: "AUB", Kickoff: kickoff, Total: 50},
+			want: map[string]string{"week": "Week is required"},
+		},
+		{
+			name: "week must match the target week",
+			into: &week2,
+			row:  Row{Week: 1, HomeTeam: "ALA", AwayTeam: "AUB", Kickoff: kickoff, Total: 50},
+			want: map[string]string{"week": "Rows must be for week 2"},
+		},
+		{
+			name: "validation failures use column names",
+			into: &week2,
+			row:  Row{HomeTeam: "ALA", AwayTeam: "Alabama", Total: 0},
+			want: map[string]string{
+				"teams":   "Home team and away team cannot be the same",
+				"kickoff": "Game time is required",
+				"total":   "Total must be greater than 0",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			games, rowErrors, err := Plan(db, 1, tt.into, []Row{tt.row}, resolver)
+			assert.NoError(t, err)
+			assert.Empty(t, games)
+			assert.Equal(t, []RowError{{Row: 1, Details: tt.want}}, rowErrors)
+		})
+	}
+}
+
+func TestPlan_DuplicateRows(t *testing.T) {
+	db, resolver := setupTestDB(t)
+	kickoff := time.Now().Add(72 * time.Hour).UTC().Format(time.RFC3339)
+
+	row := Row{Week: 1, HomeTeam: "ALA", AwayTeam: "AUB", Kickoff: kickoff, HomeSpread: -7, Total: 50}
+	games, rowErrors, err := Plan(db, 1, nil, []Row{row, row}, resolver)
+	assert.NoError(t, err)
+	assert.Empty(t, games)
+	assert.Equal(t, []RowError{{Row: 2, Details: map[string]string{"game": "Duplicate of row 1"}}}, rowErrors)
+}
@@ -0,0 +1,35 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Job is a unit of background work run on an interval
+type Job func(ctx context.Context) error
+
+// Every runs job immediately and then on every interval until ctx is cancelled
+// Running once up front lets jobs catch up on anything missed while the server was down.
+// Errors are logged and the job keeps running on its schedule
+func Every(ctx context.Context, interval time.Duration, name string, job Job) {
+	run := func() {
+		if err := job(ctx); err != nil {
+			log.Printf("Scheduled job %q failed: %v", name, err)
+		}
+	}
+
+	run()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			run()
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package scores
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// FixtureProvider reads scores from a JSON file or URL holding a list of GameScore
+// It lets the poller run against a local stand-in for a real scores feed
+type FixtureProvider struct {
+	source string
+	client *http.Client
+}
+
+// NewFixtureProvider creates a provider for a file path or an http(s) URL
+// The source is re-read on every poll, so edits to a fixture file show up live
+func NewFixtureProvider(source string) *FixtureProvider {
+	return &FixtureProvider{
+		source: source,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name implements Provider
+func (f *FixtureProvider) Name() string {
+	return "fixture " + f.source
+}
+
+// Scores implements Provider
+func (f *FixtureProvider) Scores(ctx context.Context) ([]GameScore, error) {
+	body, err := f.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var feed []GameScore
+	if err := json.Unmarshal(body, &feed); err != nil {
+		return nil, fmt.Errorf("invalid scores fixture: %w", err)
+	}
+	return feed, nil
+}
+
+// read loads the raw fixture from disk or over HTTP
+func (f *FixtureProvider) read(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(f.source, "http://") && !strings.HasPrefix(f.source, "https://") {
+		return os.ReadFile(f.source)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("scores fixture returned %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
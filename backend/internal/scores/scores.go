@@ -0,0 +1,198 @@
+package scores
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/results"
+	"github.com/ckinger23/mountaintop/internal/weeks"
+	"gorm.io/gorm"
+)
+
+// GameScore is a provider's current state for one game
+type GameScore struct {
+	ExternalID string    `json:"id"`
+	HomeTeam   string    `json:"home_team"` // Team abbreviation
+	AwayTeam   string    `json:"away_team"` // Team abbreviation
+	Kickoff    time.Time `json:"kickoff"`
+	HomeScore  int       `json:"home_score"`
+	AwayScore  int       `json:"away_score"`
+	Period     int       `json:"period"` // 0 before kickoff, 1-4, 5 and up for overtime
+	Clock      string    `json:"clock"`
+	Final      bool      `json:"final"`
+}
+
+// Provider reports live scores from a scores feed
+type Provider interface {
+	// Name identifies the provider in logs and result history
+	Name() string
+	// Scores returns the current state of every game the feed knows about
+	Scores(ctx context.Context) ([]GameScore, error)
+}
+
+// Poller applies a provider's scores to games that have kicked off
+type Poller struct {
+	db       *gorm.DB
+	provider Provider
+}
+
+// NewPoller creates a poller that reads scores from provider
+func NewPoller(db *gorm.DB, provider Provider) *Poller {
+	return &Poller{db: db, provider: provider}
+}
+
+// Poll updates the score, period and clock of every game that has kicked off and isn't final yet
+// Games the provider reports as final are finalized through results.Record, or results.RecordScheduled for games
+// selected from the master schedule, the same paths as admin-entered results.
+// The provider isn't called when no games are live
+func (p *Poller) Poll(ctx context.Context, now time.Time) error {
+	var games []models.Game
+	if err := p.db.Where("status IN ? AND is_final = ?", []string{models.GameStatusScheduled, models.GameStatusInProgress}, false).
+		Preload("HomeTeam").
+		Preload("AwayTeam").
+		Preload("Week").
+		Find(&games).Error; err != nil {
+		return err
+	}
+
+	// Kickoffs are compared in Go since SQLite stores times as text with their original offset
+	var live []models.Game
+	for _, game := range games {
+		if !game.GameTime.After(now) {
+			live = append(live, game)
+		}
+	}
+	if len(live) == 0 {
+		return nil
+	}
+
+	feed, err := p.provider.Scores(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch scores from %s: %w", p.provider.Name(), err)
+	}
+
+	var errs []error
+	for i := range live {
+		score, ok := Match(&live[i], feed)
+		if !ok {
+			continue
+		}
+		if err := p.apply(&live[i], score); err != nil {
+			errs = append(errs, fmt.Errorf("game %d: %w", live[i].ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// apply writes one provider score to a game
+// Games selected from the master schedule are scored there, so every league's copy stays in step
+func (p *Poller) apply(game *models.Game, score GameScore) error {
+	if score.Final {
+		actor := weeks.SchedulerActor(fmt.Sprintf("final score from %s", p.provider.Name()))
+		submission := results.Submission{HomeScore: score.HomeScore, AwayScore: score.AwayScore, IsFinal: true}
+		recorded := false
+		err := p.db.Transaction(func(tx *gorm.DB) error {
+			// Poll loaded the game before calling the provider, so reload it to avoid
+			// overwriting a result, status change or edit made in the meantime
+			var fresh models.Game
+			if err := tx.Preload("Week").First(&fresh, game.ID).Error; err != nil {
+				return err
+			}
+			if fresh.IsFinal || !isLive(fresh.Status) {
+				return nil
+			}
+
+			finished := tx.Model(&models.Game{}).Where("id = ?", fresh.ID)
+			if fresh.ScheduledGameID != nil {
+				var sg models.ScheduledGame
+				if err := tx.First(&sg, *fresh.ScheduledGameID).Error; err != nil {
+					return err
+				}
+				if sg.IsFinal || !isLive(sg.Status) {
+					return nil
+				}
+				if _, err := results.RecordScheduled(tx, &sg, submission, actor); err != nil {
+					return err
+				}
+				finished = tx.Model(&models.Game{}).Where("scheduled_game_id = ? AND is_final = ?", sg.ID, true)
+			} else if _, err := results.Record(tx, &fresh, submission, actor); err != nil {
+				return err
+			}
+			recorded = true
+			return finished.Updates(map[string]interface{}{"period": score.Period, "clock": ""}).Error
+		})
+		if err != nil || !recorded {
+			return err
+		}
+
+		log.Printf("Finalized game %d from %s: %d-%d", game.ID, p.provider.Name(), score.HomeScore, score.AwayScore)
+		return nil
+	}
+
+	// Not started yet, or nothing new since the last poll
+	if score.Period == 0 {
+		return nil
+	}
+	if game.Status == models.GameStatusInProgress && game.Period == score.Period && game.Clock == score.Clock &&
+		game.HomeScore != nil && *game.HomeScore == score.HomeScore &&
+		game.AwayScore != nil && *game.AwayScore == score.AwayScore {
+		return nil
+	}
+
+	live := []string{models.GameStatusScheduled, models.GameStatusInProgress}
+	updates := map[string]interface{}{
+		"status":     models.GameStatusInProgress,
+		"home_score": score.HomeScore,
+		"away_score": score.AwayScore,
+	}
+
+	// The is_final and status guards keep a slow poll from overwriting a result or status change made in the meantime
+	games := p.db.Model(&models.Game{}).Where("id = ? AND is_final = ? AND status IN ?", game.ID, false, live)
+	if game.ScheduledGameID != nil {
+		if err := p.db.Model(&models.ScheduledGame{}).
+			Where("id = ? AND is_final = ? AND status IN ?", *game.ScheduledGameID, false, live).
+			Updates(updates).Error; err != nil {
+			return err
+		}
+		games = p.db.Model(&models.Game{}).Where("scheduled_game_id = ? AND is_final = ? AND status IN ?", *game.ScheduledGameID, false, live)
+	}
+
+	updates["period"] = score.Period
+	updates["clock"] = score.Clock
+	return games.Updates(updates).Error
+}
+
+// isLive reports whether a game with this status can still get a score
+func isLive(status string) bool {
+	return status == models.GameStatusScheduled || status == models.GameStatusInProgress
+}
+
+// Match finds the provider score for a game
+// Games with an ExternalID match on it; otherwise the teams must match and the kickoff must be within a day.
+// game must have its HomeTeam and AwayTeam loaded
+func Match(game *models.Game, feed []GameScore) (GameScore, bool) {
+	for _, score := range feed {
+		if game.ExternalID != "" {
+			if score.ExternalID == game.ExternalID {
+				return score, true
+			}
+			continue
+		}
+
+		if !strings.EqualFold(score.HomeTeam, game.HomeTeam.Abbreviation) || !strings.EqualFold(score.AwayTeam, game.AwayTeam.Abbreviation) {
+			continue
+		}
+		if !score.Kickoff.IsZero() {
+			if gap := score.Kickoff.Sub(game.GameTime); gap > 24*time.Hour || gap < -24*time.Hour {
+				continue
+			}
+		}
+		return score, true
+	}
+	return GameScore{}, false
+}
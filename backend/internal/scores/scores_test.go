@@ -0,0 +1,251 @@
+package scores
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupTestDB creates an in-memory SQLite database for testing
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+
+	err = db.AutoMigrate(
+		&models.LeagueSettings{},
+		&models.User{},
+		&models.Season{},
+		&models.Week{},
+		&models.WeekTransition{},
+		&models.Team{},
+		&models.ScheduledGame{},
+		&models.Game{},
+		&models.GameResult{},
+		&models.Pick{},
+		&models.Standing{},
+		&models.SurvivorPick{},
+		&models.BracketSlot{},
+		&models.BracketPick{},
+	)
+	if err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	return db
+}
+
+// staticProvider returns a fixed feed and counts how often it's called
+type staticProvider struct {
+	feed  []GameScore
+	calls int
+}
+
+func (p *staticProvider) Name() string { return "static" }
+
+func (p *staticProvider) Scores(ctx context.Context) ([]GameScore, error) {
+	p.calls++
+	return p.feed, nil
+}
+
+// seedGame creates a game between two teams kicking off at kickoff, with one home pick
+func seedGame(t *testing.T, db *gorm.DB, kickoff time.Time) models.Game {
+	week := models.Week{SeasonID: 1, WeekNumber: 1, Name: "Week 1", Status: "scoring"}
+	db.Create(&week)
+
+	home := models.Team{Name: "Alabama", Abbreviation: "ALA"}
+	away := models.Team{Name: "Auburn", Abbreviation: "AUB"}
+	db.Create(&home)
+	db.Create(&away)
+
+	game := models.Game{WeekID: week.ID, HomeTeamID: home.ID, AwayTeamID: away.ID, GameTime: kickoff, HomeSpread: -7, Total: 50.5}
+	if err := db.Create(&game).Error; err != nil {
+		t.Fatalf("Failed to create game: %v", err)
+	}
+	db.Create(&models.Pick{LeagueID: 1, UserID: 1, GameID: game.ID, PickedTeamID: home.ID, PickedOverUnder: "under"})
+	return game
+}
+
+func TestPoller_Poll(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Now()
+	game := seedGame(t, db, now.Add(-time.Hour))
+
+	provider := &staticProvider{feed: []GameScore{
+		{HomeTeam: "ala", AwayTeam: "aub", Kickoff: now.Add(-time.Hour), HomeScore: 14, AwayScore: 10, Period: 2, Clock: "3:12"},
+	}}
+	poller := NewPoller(db, provider)
+
+	// In-progress scores update the game without grading picks
+	assert.NoError(t, poller.Poll(context.Background(), now))
+
+	var updated models.Game
+	db.First(&updated, game.ID)
+	assert.Equal(t, models.GameStatusInProgress, updated.Status)
+	assert.Equal(t, 14, *updated.HomeScore)
+	assert.Equal(t, 2, updated.Period)
+	assert.Equal(t, "3:12", updated.Clock)
+	assert.False(t, updated.IsFinal)
+
+	var pick models.Pick
+	db.First(&pick)
+	assert.Equal(t, models.PickOutcomePending, pick.SpreadOutcome)
+
+	// A final score goes through the result path: picks are graded and history is kept
+	provider.feed[0] = GameScore{HomeTeam: "ALA", AwayTeam: "AUB", HomeScore: 31, AwayScore: 17, Period: 4, Final: true}
+	assert.NoError(t, poller.Poll(context.Background(), now))
+
+	db.First(&updated, game.ID)
+	assert.True(t, updated.IsFinal)
+	assert.Equal(t, models.GameStatusFinal, updated.Status)
+	assert.Equal(t, "", updated.Clock)
+
+	db.First(&pick)
+	assert.Equal(t, models.PickOutcomeWin, pick.SpreadOutcome)
+	assert.Equal(t, models.PickOutcomeWin, pick.OverUnderOutcome)
+
+	var history models.GameResult
+	assert.NoError(t, db.Where("game_id = ?", game.ID).First(&history).Error)
+	assert.Equal(t, "scheduler", history.Source)
+	assert.Contains(t, history.Reason, "static")
+
+	// Final games aren't polled again
+	calls := provider.calls
+	assert.NoError(t, poller.Poll(context.Background(), now))
+	assert.Equal(t, calls, provider.calls)
+}
+
+func TestPoller_Apply_StaleGame(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Now()
+	game := seedGame(t, db, now.Add(-time.Hour))
+	poller := NewPoller(db, &staticProvider{})
+
+	// The game is postponed after Poll loaded it but before the final score is applied
+	db.Model(&models.Game{}).Where("id = ?", game.ID).Update("status", models.GameStatusPostponed)
+
+	assert.NoError(t, poller.apply(&game, GameScore{HomeScore: 31, AwayScore: 17, Period: 4, Final: true}))
+
+	var updated models.Game
+	db.First(&updated, game.ID)
+	assert.False(t, updated.IsFinal)
+	assert.Equal(t, models.GameStatusPostponed, updated.Status)
+	assert.Nil(t, updated.HomeScore)
+
+	var count int64
+	db.Model(&models.GameResult{}).Where("game_id = ?", game.ID).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestPoller_Poll_SharedGame(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Now()
+	game := seedGame(t, db, now.Add(-time.Hour))
+
+	// A second league has the same master schedule game on its slate
+	sg := models.ScheduledGame{Year: 2025, WeekNumber: 1, HomeTeamID: game.HomeTeamID, AwayTeamID: game.AwayTeamID, GameTime: game.GameTime, HomeSpread: -7, Total: 50.5}
+	db.Create(&sg)
+	db.Model(&game).Update("scheduled_game_id", sg.ID)
+	copied := models.Game{WeekID: game.WeekID, HomeTeamID: game.HomeTeamID, AwayTeamID: game.AwayTeamID, GameTime: game.GameTime, HomeSpread: -7, Total: 50.5, ScheduledGameID: &sg.ID}
+	db.Create(&copied)
+
+	provider := &staticProvider{feed: []GameScore{{HomeTeam: "ALA", AwayTeam: "AUB", HomeScore: 7, AwayScore: 0, Period: 1, Clock: "9:00"}}}
+	poller := NewPoller(db, provider)
+
+	// Live scores reach the master game and every copy
+	assert.NoError(t, poller.Poll(context.Background(), now))
+	var updated models.Game
+	db.First(&updated, copied.ID)
+	assert.Equal(t, 7, *updated.HomeScore)
+	assert.Equal(t, "9:00", updated.Clock)
+	var master models.ScheduledGame
+	db.First(&master, sg.ID)
+	assert.Equal(t, models.GameStatusInProgress, master.Status)
+
+	// The final score is recorded once on the master game
+	provider.feed[0] = GameScore{HomeTeam: "ALA", AwayTeam: "AUB", HomeScore: 31, AwayScore: 17, Period: 4, Final: true}
+	assert.NoError(t, poller.Poll(context.Background(), now))
+
+	db.First(&master, sg.ID)
+	assert.True(t, master.IsFinal)
+	for _, id := range []uint{game.ID, copied.ID} {
+		updated = models.Game{}
+		db.First(&updated, id)
+		assert.True(t, updated.IsFinal)
+		assert.Equal(t, 4, updated.Period)
+	}
+
+	var count int64
+	db.Model(&models.GameResult{}).Where("game_id = ?", game.ID).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestPoller_Poll_NoLiveGames(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Now()
+	seedGame(t, db, now.Add(time.Hour))
+
+	provider := &staticProvider{}
+	assert.NoError(t, NewPoller(db, provider).Poll(context.Background(), now))
+	assert.Equal(t, 0, provider.calls)
+}
+
+func TestMatch(t *testing.T) {
+	kickoff := time.Date(2025, 11, 29, 19, 30, 0, 0, time.UTC)
+	game := &models.Game{
+		GameTime: kickoff,
+		HomeTeam: models.Team{Abbreviation: "ALA"},
+		AwayTeam: models.Team{Abbreviation: "AUB"},
+	}
+
+	// Teams match within a day of kickoff
+	_, ok := Match(game, []GameScore{{HomeTeam: "ALA", AwayTeam: "AUB", Kickoff: kickoff.Add(2 * time.Hour)}})
+	assert.True(t, ok)
+	_, ok = Match(game, []GameScore{{HomeTeam: "ALA", AwayTeam: "AUB", Kickoff: kickoff.AddDate(0, 0, 7)}})
+	assert.False(t, ok)
+	_, ok = Match(game, []GameScore{{HomeTeam: "AUB", AwayTeam: "ALA", Kickoff: kickoff}})
+	assert.False(t, ok)
+
+	// External IDs take precedence over teams
+	game.ExternalID = "401"
+	_, ok = Match(game, []GameScore{{HomeTeam: "ALA", AwayTeam: "AUB", Kickoff: kickoff}})
+	assert.False(t, ok)
+	score, ok := Match(game, []GameScore{{ExternalID: "401", HomeScore: 3}})
+	assert.True(t, ok)
+	assert.Equal(t, 3, score.HomeScore)
+}
+
+func TestFixtureProvider(t *testing.T) {
+	fixture := `[{"id": "401", "home_team": "ALA", "away_team": "AUB", "home_score": 7, "away_score": 3, "period": 1, "clock": "4:00"}]`
+
+	path := filepath.Join(t.TempDir(), "scores.json")
+	if err := os.WriteFile(path, []byte(fixture), 0o644); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(fixture))
+	}))
+	defer server.Close()
+
+	for _, source := range []string{path, server.URL} {
+		feed, err := NewFixtureProvider(source).Scores(context.Background())
+		assert.NoError(t, err)
+		if assert.Len(t, feed, 1) {
+			assert.Equal(t, GameScore{ExternalID: "401", HomeTeam: "ALA", AwayTeam: "AUB", HomeScore: 7, AwayScore: 3, Period: 1, Clock: "4:00"}, feed[0])
+		}
+	}
+
+	_, err := NewFixtureProvider(filepath.Join(t.TempDir(), "missing.json")).Scores(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,144 @@
+package scoring
+
+import (
+	"errors"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"gorm.io/gorm"
+)
+
+// Rules grades and scores picks for a league
+// Every path that writes pick results must go through Rules so leaderboard totals stay consistent
+type Rules interface {
+	// Score sets the outcomes and points earned on a pick for a final game
+	Score(game *models.Game, pick *models.Pick)
+}
+
+// Config is the standard spread + over/under scoring, with configurable point values
+type Config struct {
+	SpreadPoints     int  // Points for covering the spread
+	OverUnderPoints  int  // Points for a correct over/under
+	PushPoints       int  // Points awarded when a pick pushes
+	UpsetBonusPoints int  // Extra points for picking an underdog that wins outright
+	OverUnderEnabled bool // When false, over/under picks are voided
+}
+
+// ConfidenceRules scores a confidence pool
+// A covered spread earns the pick's confidence value instead of a flat SpreadPoints
+type ConfidenceRules struct {
+	Config
+}
+
+// DefaultSettings returns the settings used by leagues that haven't customized scoring
+// (1 point spread + 1 point over/under)
+func DefaultSettings(leagueID uint) models.LeagueSettings {
+	return models.LeagueSettings{
+		LeagueID:         leagueID,
+		Format:           models.LeagueFormatStandard,
+		SpreadPoints:     1,
+		OverUnderPoints:  1,
+		PushPoints:       0,
+		UpsetBonusPoints: 0,
+		OverUnderEnabled: true,
+		PostponedPicks:   models.PostponedPicksKeep,
+		AutoPick:         models.AutoPickNone,
+		Tiebreakers:      models.TiebreakerCorrectPicks + "," + models.TiebreakerSpreadWins,
+		RankingStyle:     models.RankingStandard,
+		GuessRule:        models.GuessWithoutGoingOver,
+	}
+}
+
+// DefaultConfig returns the standard "1 point spread + 1 point over/under" scoring
+func DefaultConfig() Config {
+	return NewConfig(DefaultSettings(0))
+}
+
+// NewConfig builds scoring rules from a league's settings
+func NewConfig(settings models.LeagueSettings) Config {
+	return Config{
+		SpreadPoints:     settings.SpreadPoints,
+		OverUnderPoints:  settings.OverUnderPoints,
+		PushPoints:       settings.PushPoints,
+		UpsetBonusPoints: settings.UpsetBonusPoints,
+		OverUnderEnabled: settings.OverUnderEnabled,
+	}
+}
+
+// Score implements Rules
+func (c Config) Score(game *models.Game, pick *models.Pick) {
+	c.score(game, pick, c.SpreadPoints)
+}
+
+// Score implements Rules
+func (c ConfidenceRules) Score(game *models.Game, pick *models.Pick) {
+	c.score(game, pick, pick.Confidence)
+}
+
+// score grades a pick against the line it was made on and awards spreadWinPoints for a covered spread
+func (c Config) score(game *models.Game, pick *models.Pick, spreadWinPoints int) {
+	game = PickedLine(game, pick)
+
+	pick.SpreadOutcome = GradeSpread(game, pick.PickedTeamID)
+
+	pick.OverUnderOutcome = models.PickOutcomeVoid
+	if c.OverUnderEnabled {
+		pick.OverUnderOutcome = GradeOverUnder(game, pick.PickedOverUnder)
+	}
+
+	pick.PointsEarned = c.pointsFor(pick.SpreadOutcome, spreadWinPoints) + c.OverUnderPointsFor(pick.OverUnderOutcome)
+	if IsUpset(game, pick.PickedTeamID) {
+		pick.PointsEarned += c.UpsetBonusPoints
+	}
+}
+
+// SpreadPointsFor returns the points earned for a graded spread pick
+func (c Config) SpreadPointsFor(outcome models.PickOutcome) int {
+	return c.pointsFor(outcome, c.SpreadPoints)
+}
+
+// OverUnderPointsFor returns the points earned for a graded over/under pick
+func (c Config) OverUnderPointsFor(outcome models.PickOutcome) int {
+	return c.pointsFor(outcome, c.OverUnderPoints)
+}
+
+func (c Config) pointsFor(outcome models.PickOutcome, winPoints int) int {
+	switch outcome {
+	case models.PickOutcomeWin:
+		return winPoints
+	case models.PickOutcomePush:
+		return c.PushPoints
+	default:
+		return 0
+	}
+}
+
+// LoadSettings returns a league's settings, falling back to the defaults if none are saved
+func LoadSettings(db *gorm.DB, leagueID uint) (models.LeagueSettings, error) {
+	var settings models.LeagueSettings
+	err := db.Where("league_id = ?", leagueID).First(&settings).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return DefaultSettings(leagueID), nil
+	}
+	if err != nil {
+		return models.LeagueSettings{}, err
+	}
+	return settings, nil
+}
+
+// RulesForLeague loads the scoring rules configured for a league
+func RulesForLeague(db *gorm.DB, leagueID uint) (Rules, error) {
+	settings, err := LoadSettings(db, leagueID)
+	if err != nil {
+		return nil, err
+	}
+	return NewRules(settings), nil
+}
+
+// NewRules returns the scoring rules for a league's format
+func NewRules(settings models.LeagueSettings) Rules {
+	config := NewConfig(settings)
+	if settings.Format == models.LeagueFormatConfidence {
+		return ConfidenceRules{Config: config}
+	}
+	return config
+}
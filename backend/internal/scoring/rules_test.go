@@ -0,0 +1,140 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestConfig_SpreadPointsFor(t *testing.T) {
+	cfg := DefaultConfig()
+	assert.Equal(t, 1, cfg.SpreadPointsFor(models.PickOutcomeWin))
+	assert.Equal(t, 0, cfg.SpreadPointsFor(models.PickOutcomeLoss))
+	assert.Equal(t, 0, cfg.SpreadPointsFor(models.PickOutcomePush))
+
+	// Pushes can be configured to award points
+	cfg.PushPoints = 1
+	assert.Equal(t, 1, cfg.SpreadPointsFor(models.PickOutcomePush))
+	assert.Equal(t, 0, cfg.SpreadPointsFor(models.PickOutcomeLoss))
+	assert.Equal(t, 1, cfg.OverUnderPointsFor(models.PickOutcomePush))
+	assert.Equal(t, 0, cfg.SpreadPointsFor(models.PickOutcomeVoid))
+}
+
+func TestConfig_Score(t *testing.T) {
+	withBonus := DefaultConfig()
+	withBonus.UpsetBonusPoints = 2
+
+	spreadOnly := DefaultConfig()
+	spreadOnly.OverUnderEnabled = false
+
+	pushPays := DefaultConfig()
+	pushPays.PushPoints = 1
+
+	tests := []struct {
+		name          string
+		rules         Config
+		game          *models.Game
+		picked        uint
+		overUnder     string
+		wantSpread    models.PickOutcome
+		wantOverUnder models.PickOutcome
+		wantPoints    int
+	}{
+		{"default both correct", DefaultConfig(), newGameWithTotal(-3.5, 44.5, 28, 21), homeID, "over", models.PickOutcomeWin, models.PickOutcomeWin, 2},
+		{"default both wrong", DefaultConfig(), newGameWithTotal(-3.5, 44.5, 28, 21), awayID, "under", models.PickOutcomeLoss, models.PickOutcomeLoss, 0},
+		{"upset bonus on outright underdog win", withBonus, newGameWithTotal(-3.5, 44.5, 21, 28), awayID, "under", models.PickOutcomeWin, models.PickOutcomeLoss, 3},
+		{"no upset bonus for favorite", withBonus, newGameWithTotal(-3.5, 44.5, 28, 21), homeID, "under", models.PickOutcomeWin, models.PickOutcomeLoss, 1},
+		{"spread-only voids over/under", spreadOnly, newGameWithTotal(-3.5, 44.5, 28, 21), homeID, "over", models.PickOutcomeWin, models.PickOutcomeVoid, 1},
+		{"pushes earn push points", pushPays, newGameWithTotal(-7, 49, 28, 21), homeID, "over", models.PickOutcomePush, models.PickOutcomePush, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pick := models.Pick{PickedTeamID: tt.picked, PickedOverUnder: tt.overUnder}
+			tt.rules.Score(tt.game, &pick)
+			assert.Equal(t, tt.wantSpread, pick.SpreadOutcome)
+			assert.Equal(t, tt.wantOverUnder, pick.OverUnderOutcome)
+			assert.Equal(t, tt.wantPoints, pick.PointsEarned)
+		})
+	}
+}
+
+func TestRulesForLeague(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.LeagueSettings{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	// Leagues without saved settings get the defaults
+	rules, err := RulesForLeague(db, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultConfig(), rules)
+
+	// Saved settings are used, including zero values
+	settings := DefaultSettings(2)
+	settings.SpreadPoints = 3
+	settings.OverUnderEnabled = false
+	db.Create(&settings)
+
+	rules, err = RulesForLeague(db, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, Config{SpreadPoints: 3, OverUnderPoints: 1, OverUnderEnabled: false}, rules)
+}
+
+func TestConfidenceRules_Score(t *testing.T) {
+	settings := DefaultSettings(1)
+	settings.Format = models.LeagueFormatConfidence
+	settings.OverUnderEnabled = false
+	rules := NewRules(settings)
+
+	assert.IsType(t, ConfidenceRules{}, rules)
+
+	tests := []struct {
+		name       string
+		game       *models.Game
+		picked     uint
+		confidence int
+		wantPoints int
+	}{
+		{"correct pick earns its confidence", newGame(-3.5, 28, 21), homeID, 7, 7},
+		{"incorrect pick earns nothing", newGame(-3.5, 28, 21), awayID, 7, 0},
+		{"push earns push points", newGame(-7, 28, 21), homeID, 7, 0},
+		{"low confidence correct pick", newGame(3.5, 17, 14), homeID, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pick := models.Pick{PickedTeamID: tt.picked, Confidence: tt.confidence}
+			rules.Score(tt.game, &pick)
+			assert.Equal(t, tt.wantPoints, pick.PointsEarned)
+			assert.Equal(t, models.PickOutcomeVoid, pick.OverUnderOutcome)
+		})
+	}
+}
+
+func TestConfig_Score_PickedLine(t *testing.T) {
+	// The line moved from -3.5 / 44.5 to -10.5 / 50.5 after the pick was made
+	game := newGameWithTotal(-10.5, 50.5, 28, 21)
+	spread, total := -3.5, 44.5
+
+	pick := models.Pick{PickedTeamID: homeID, PickedOverUnder: "over", HomeSpread: &spread, Total: &total}
+	DefaultConfig().Score(game, &pick)
+	assert.Equal(t, models.PickOutcomeWin, pick.SpreadOutcome)
+	assert.Equal(t, models.PickOutcomeWin, pick.OverUnderOutcome)
+	assert.Equal(t, 2, pick.PointsEarned)
+
+	// Picks without a snapshot use the current line
+	legacy := models.Pick{PickedTeamID: homeID, PickedOverUnder: "over"}
+	DefaultConfig().Score(game, &legacy)
+	assert.Equal(t, models.PickOutcomeLoss, legacy.SpreadOutcome)
+	assert.Equal(t, models.PickOutcomeLoss, legacy.OverUnderOutcome)
+
+	// The game itself is left untouched
+	assert.Equal(t, -10.5, game.HomeSpread)
+}
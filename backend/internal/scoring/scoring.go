@@ -0,0 +1,83 @@
+package scoring
+
+import (
+	"github.com/ckinger23/mountaintop/internal/models"
+)
+
+// GradeSpread grades a pick against the spread
+// The home team covers when home score + HomeSpread beats the away score
+// A whole-number spread that lands exactly on the margin is a push
+// The game must have both scores set
+func GradeSpread(game *models.Game, pickedTeamID uint) models.PickOutcome {
+	margin := float64(*game.HomeScore) + game.HomeSpread - float64(*game.AwayScore)
+
+	if margin == 0 {
+		return models.PickOutcomePush
+	}
+
+	homeCovered := margin > 0
+	if (pickedTeamID == game.HomeTeamID) == homeCovered {
+		return models.PickOutcomeWin
+	}
+	return models.PickOutcomeLoss
+}
+
+// GradeOverUnder grades an "over" or "under" pick against the game total
+// A combined score equal to a whole-number total is a push
+// The game must have both scores set
+func GradeOverUnder(game *models.Game, pickedOverUnder string) models.PickOutcome {
+	actualTotal := float64(*game.HomeScore + *game.AwayScore)
+
+	if actualTotal == game.Total {
+		return models.PickOutcomePush
+	}
+
+	wentOver := actualTotal > game.Total
+	switch pickedOverUnder {
+	case "over":
+		if wentOver {
+			return models.PickOutcomeWin
+		}
+	case "under":
+		if !wentOver {
+			return models.PickOutcomeWin
+		}
+	}
+	return models.PickOutcomeLoss
+}
+
+// PickedLine returns a copy of the game carrying the spread and total snapshotted on the pick
+// Picks without a snapshot are graded against the game's current line
+func PickedLine(game *models.Game, pick *models.Pick) *models.Game {
+	line := *game
+	if pick.HomeSpread != nil {
+		line.HomeSpread = *pick.HomeSpread
+	}
+	if pick.Total != nil {
+		line.Total = *pick.Total
+	}
+	return &line
+}
+
+// IsUpset reports whether the picked team was the underdog and won outright
+// Pick'em games (zero spread) have no underdog
+func IsUpset(game *models.Game, pickedTeamID uint) bool {
+	var underdogID uint
+	switch {
+	case game.HomeSpread > 0:
+		underdogID = game.HomeTeamID
+	case game.HomeSpread < 0:
+		underdogID = game.AwayTeamID
+	default:
+		return false
+	}
+
+	if pickedTeamID != underdogID {
+		return false
+	}
+
+	if underdogID == game.HomeTeamID {
+		return *game.HomeScore > *game.AwayScore
+	}
+	return *game.AwayScore > *game.HomeScore
+}
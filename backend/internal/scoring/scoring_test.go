@@ -0,0 +1,123 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	homeID uint = 1
+	awayID uint = 2
+)
+
+// newGame builds a final game with the given spread and score
+func newGame(homeSpread float64, homeScore, awayScore int) *models.Game {
+	return newGameWithTotal(homeSpread, 0, homeScore, awayScore)
+}
+
+// newGameWithTotal builds a final game with the given spread, total and score
+func newGameWithTotal(homeSpread, total float64, homeScore, awayScore int) *models.Game {
+	return &models.Game{
+		HomeTeamID: homeID,
+		AwayTeamID: awayID,
+		HomeSpread: homeSpread,
+		Total:      total,
+		IsFinal:    true,
+		HomeScore:  &homeScore,
+		AwayScore:  &awayScore,
+	}
+}
+
+func TestGradeSpread(t *testing.T) {
+	tests := []struct {
+		name       string
+		homeSpread float64
+		homeScore  int
+		awayScore  int
+		picked     uint
+		want       models.PickOutcome
+	}{
+		// Home favorite (negative spread)
+		{"home favorite covers", -7.0, 31, 21, homeID, models.PickOutcomeWin},
+		{"home favorite wins but fails to cover", -7.5, 28, 21, homeID, models.PickOutcomeLoss},
+		{"away underdog covers in a loss", -7.5, 28, 21, awayID, models.PickOutcomeWin},
+		{"away underdog wins outright", -3.5, 17, 24, awayID, models.PickOutcomeWin},
+
+		// Home underdog (positive spread)
+		{"home underdog covers in a loss", 10.5, 20, 30, homeID, models.PickOutcomeWin},
+		{"home underdog fails to cover", 3.5, 10, 21, homeID, models.PickOutcomeLoss},
+		{"away favorite covers", 3.5, 10, 21, awayID, models.PickOutcomeWin},
+		{"away favorite wins but fails to cover", 6.5, 17, 21, awayID, models.PickOutcomeLoss},
+
+		// Pick'em and ties
+		{"pick'em home wins", 0, 24, 21, homeID, models.PickOutcomeWin},
+		{"pick'em tie is a push", 0, 21, 21, homeID, models.PickOutcomePush},
+		{"tie with home favored loses for home", -2.5, 21, 21, homeID, models.PickOutcomeLoss},
+		{"tie with home favored wins for away", -2.5, 21, 21, awayID, models.PickOutcomeWin},
+
+		// Pushes on whole-number spreads
+		{"home favorite lands on number", -7.0, 28, 21, homeID, models.PickOutcomePush},
+		{"away side of same push", -7.0, 28, 21, awayID, models.PickOutcomePush},
+		{"home underdog lands on number", 3.0, 21, 24, homeID, models.PickOutcomePush},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GradeSpread(newGame(tt.homeSpread, tt.homeScore, tt.awayScore), tt.picked)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGradeOverUnder(t *testing.T) {
+	tests := []struct {
+		name      string
+		total     float64
+		homeScore int
+		awayScore int
+		picked    string
+		want      models.PickOutcome
+	}{
+		{"over hits", 48.5, 28, 24, "over", models.PickOutcomeWin},
+		{"over misses", 48.5, 21, 17, "over", models.PickOutcomeLoss},
+		{"under hits", 48.5, 21, 17, "under", models.PickOutcomeWin},
+		{"under misses", 48.5, 28, 24, "under", models.PickOutcomeLoss},
+		{"over push on whole number", 45, 24, 21, "over", models.PickOutcomePush},
+		{"under push on whole number", 45, 24, 21, "under", models.PickOutcomePush},
+		{"missing pick is a loss", 48.5, 28, 24, "", models.PickOutcomeLoss},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GradeOverUnder(newGameWithTotal(0, tt.total, tt.homeScore, tt.awayScore), tt.picked)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIsUpset(t *testing.T) {
+	tests := []struct {
+		name       string
+		homeSpread float64
+		homeScore  int
+		awayScore  int
+		picked     uint
+		want       bool
+	}{
+		{"home underdog wins outright", 6.5, 24, 21, homeID, true},
+		{"away underdog wins outright", -6.5, 21, 24, awayID, true},
+		{"underdog covers but loses", 6.5, 20, 24, homeID, false},
+		{"favorite wins", -6.5, 31, 21, homeID, false},
+		{"pick'em has no underdog", 0, 17, 24, awayID, false},
+		{"underdog ties", 3, 21, 21, homeID, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := IsUpset(newGame(tt.homeSpread, tt.homeScore, tt.awayScore), tt.picked)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
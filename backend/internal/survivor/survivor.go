@@ -0,0 +1,120 @@
+package survivor
+
+import (
+	"sort"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"gorm.io/gorm"
+)
+
+// Entrant is a survivor-league member's standing for a season
+type Entrant struct {
+	UserID      uint   `json:"user_id"`
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+	Alive       bool   `json:"alive"`
+	WeeksPicked int    `json:"weeks_picked"`
+
+	// Set once the member is eliminated
+	EliminatedWeekID     *uint  `json:"eliminated_week_id,omitempty"`
+	EliminatedWeekNumber *int   `json:"eliminated_week_number,omitempty"`
+	EliminatedWeekName   string `json:"eliminated_week_name,omitempty"`
+}
+
+// GradeGame sets the outcome of every survivor pick on a final game
+// Survivor picks are straight-up: only an outright win survives, ties are eliminations
+// This function should be called within a transaction
+func GradeGame(tx *gorm.DB, game *models.Game) error {
+	if !game.IsFinal || game.HomeScore == nil || game.AwayScore == nil {
+		return nil
+	}
+
+	// Voided picks (e.g. on a game postponed out of the pick's week) don't count either way
+	var picks []models.SurvivorPick
+	if err := tx.Where("game_id = ? AND outcome <> ?", game.ID, models.PickOutcomeVoid).Find(&picks).Error; err != nil {
+		return err
+	}
+
+	for i := range picks {
+		picks[i].Outcome = models.PickOutcomeLoss
+		if game.WinnerTeamID != nil && *game.WinnerTeamID == picks[i].TeamID {
+			picks[i].Outcome = models.PickOutcomeWin
+		}
+
+		if err := tx.Save(&picks[i]).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IsEliminated reports whether a member has lost a survivor pick in the season
+func IsEliminated(db *gorm.DB, leagueID, seasonID, userID uint) (bool, error) {
+	var losses int64
+	err := db.Model(&models.SurvivorPick{}).
+		Where("league_id = ? AND season_id = ? AND user_id = ? AND outcome = ?", leagueID, seasonID, userID, models.PickOutcomeLoss).
+		Count(&losses).Error
+	return losses > 0, err
+}
+
+// Standings lists every league member with whether they're still alive and, if not, the week they fell
+// Alive entrants are listed first, then eliminated entrants from most to least recent
+func Standings(db *gorm.DB, leagueID, seasonID uint) ([]Entrant, error) {
+	var memberships []models.LeagueMembership
+	if err := db.Where("league_id = ?", leagueID).Preload("User").Order("user_id ASC").Find(&memberships).Error; err != nil {
+		return nil, err
+	}
+
+	var picks []models.SurvivorPick
+	if err := db.Where("league_id = ? AND season_id = ?", leagueID, seasonID).
+		Preload("Week").
+		Find(&picks).Error; err != nil {
+		return nil, err
+	}
+
+	// Group picks by user
+	picksByUser := make(map[uint][]models.SurvivorPick)
+	for _, pick := range picks {
+		picksByUser[pick.UserID] = append(picksByUser[pick.UserID], pick)
+	}
+
+	var alive, eliminated []Entrant
+	for _, m := range memberships {
+		entrant := Entrant{
+			UserID:      m.UserID,
+			Username:    m.User.Username,
+			DisplayName: m.User.DisplayName,
+			Alive:       true,
+			WeeksPicked: len(picksByUser[m.UserID]),
+		}
+
+		// The earliest losing week is where the member fell
+		for _, pick := range picksByUser[m.UserID] {
+			if pick.Outcome != models.PickOutcomeLoss {
+				continue
+			}
+			if entrant.EliminatedWeekNumber == nil || pick.Week.WeekNumber < *entrant.EliminatedWeekNumber {
+				weekID := pick.WeekID
+				weekNumber := pick.Week.WeekNumber
+				entrant.Alive = false
+				entrant.EliminatedWeekID = &weekID
+				entrant.EliminatedWeekNumber = &weekNumber
+				entrant.EliminatedWeekName = pick.Week.Name
+			}
+		}
+
+		if entrant.Alive {
+			alive = append(alive, entrant)
+		} else {
+			eliminated = append(eliminated, entrant)
+		}
+	}
+
+	// Members who lasted longer rank higher
+	sort.SliceStable(eliminated, func(i, j int) bool {
+		return *eliminated[i].EliminatedWeekNumber > *eliminated[j].EliminatedWeekNumber
+	})
+
+	return append(alive, eliminated...), nil
+}
@@ -0,0 +1,227 @@
+package survivor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupTestDB creates an in-memory SQLite database for testing
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+
+	err = db.AutoMigrate(
+		&models.League{},
+		&models.LeagueMembership{},
+		&models.User{},
+		&models.Season{},
+		&models.Week{},
+		&models.Team{},
+		&models.Game{},
+		&models.SurvivorPick{},
+	)
+	if err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	return db
+}
+
+// fixture holds the records created by seedSurvivor
+type fixture struct {
+	league models.League
+	season models.Season
+	weeks  []models.Week
+	teams  []models.Team
+	users  []models.User
+}
+
+// seedSurvivor creates a league with three members, two weeks and four teams
+func seedSurvivor(t *testing.T, db *gorm.DB) fixture {
+	var f fixture
+
+	for _, name := range []string{"alice", "bob", "charlie"} {
+		user := models.User{Username: name, Email: name + "@example.com", DisplayName: name, PasswordHash: "hash"}
+		if err := db.Create(&user).Error; err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+		f.users = append(f.users, user)
+	}
+
+	f.league = models.League{Name: "Survivor", Code: "SURV-1", OwnerID: f.users[0].ID, IsActive: true}
+	db.Create(&f.league)
+	for _, u := range f.users {
+		db.Create(&models.LeagueMembership{LeagueID: f.league.ID, UserID: u.ID, Role: "member", JoinedAt: time.Now()})
+	}
+
+	f.season = models.Season{LeagueID: f.league.ID, Year: 2025, Name: "2025", IsActive: true}
+	db.Create(&f.season)
+
+	for i := 1; i <= 2; i++ {
+		week := models.Week{SeasonID: f.season.ID, WeekNumber: i, Name: "Week", Status: "scoring"}
+		db.Create(&week)
+		f.weeks = append(f.weeks, week)
+	}
+
+	for _, abbr := range []string{"TEA", "TEB", "TEC", "TED"} {
+		team := models.Team{Name: "Team " + abbr, Abbreviation: abbr}
+		db.Create(&team)
+		f.teams = append(f.teams, team)
+	}
+
+	return f
+}
+
+// finalGame creates a final game in a week with the given score
+func finalGame(t *testing.T, db *gorm.DB, week models.Week, home, away models.Team, homeScore, awayScore int) models.Game {
+	game := models.Game{
+		WeekID:     week.ID,
+		HomeTeamID: home.ID,
+		AwayTeamID: away.ID,
+		GameTime:   time.Now(),
+		IsFinal:    true,
+		HomeScore:  &homeScore,
+		AwayScore:  &awayScore,
+	}
+	if homeScore > awayScore {
+		game.WinnerTeamID = &home.ID
+	} else if awayScore > homeScore {
+		game.WinnerTeamID = &away.ID
+	}
+	if err := db.Create(&game).Error; err != nil {
+		t.Fatalf("Failed to create game: %v", err)
+	}
+	return game
+}
+
+// pick creates a survivor pick for a user
+func pick(db *gorm.DB, f fixture, user models.User, week models.Week, game models.Game, team models.Team) {
+	db.Create(&models.SurvivorPick{
+		LeagueID: f.league.ID,
+		UserID:   user.ID,
+		SeasonID: f.season.ID,
+		WeekID:   week.ID,
+		GameID:   game.ID,
+		TeamID:   team.ID,
+	})
+}
+
+func TestGradeGame(t *testing.T) {
+	db := setupTestDB(t)
+	f := seedSurvivor(t, db)
+
+	game := finalGame(t, db, f.weeks[0], f.teams[0], f.teams[1], 28, 21)
+	pick(db, f, f.users[0], f.weeks[0], game, f.teams[0])
+	pick(db, f, f.users[1], f.weeks[0], game, f.teams[1])
+
+	assert.NoError(t, GradeGame(db, &game))
+
+	var picks []models.SurvivorPick
+	db.Order("user_id ASC").Find(&picks)
+	assert.Equal(t, models.PickOutcomeWin, picks[0].Outcome)
+	assert.Equal(t, models.PickOutcomeLoss, picks[1].Outcome)
+
+	eliminated, err := IsEliminated(db, f.league.ID, f.season.ID, f.users[0].ID)
+	assert.NoError(t, err)
+	assert.False(t, eliminated)
+
+	eliminated, err = IsEliminated(db, f.league.ID, f.season.ID, f.users[1].ID)
+	assert.NoError(t, err)
+	assert.True(t, eliminated)
+}
+
+func TestGradeGame_TieEliminates(t *testing.T) {
+	db := setupTestDB(t)
+	f := seedSurvivor(t, db)
+
+	game := finalGame(t, db, f.weeks[0], f.teams[0], f.teams[1], 21, 21)
+	pick(db, f, f.users[0], f.weeks[0], game, f.teams[0])
+
+	assert.NoError(t, GradeGame(db, &game))
+
+	eliminated, err := IsEliminated(db, f.league.ID, f.season.ID, f.users[0].ID)
+	assert.NoError(t, err)
+	assert.True(t, eliminated)
+}
+
+func TestGradeGame_IgnoresGamesNotFinal(t *testing.T) {
+	db := setupTestDB(t)
+	f := seedSurvivor(t, db)
+
+	game := finalGame(t, db, f.weeks[0], f.teams[0], f.teams[1], 28, 21)
+	pick(db, f, f.users[0], f.weeks[0], game, f.teams[1])
+
+	game.IsFinal = false
+	assert.NoError(t, GradeGame(db, &game))
+
+	var p models.SurvivorPick
+	db.First(&p)
+	assert.Equal(t, models.PickOutcomePending, p.Outcome)
+}
+
+func TestStandings(t *testing.T) {
+	db := setupTestDB(t)
+	f := seedSurvivor(t, db)
+	alice, bob, charlie := f.users[0], f.users[1], f.users[2]
+
+	// Week 1: alice and bob win, charlie loses
+	week1 := finalGame(t, db, f.weeks[0], f.teams[0], f.teams[1], 28, 21)
+	pick(db, f, alice, f.weeks[0], week1, f.teams[0])
+	pick(db, f, bob, f.weeks[0], week1, f.teams[0])
+	pick(db, f, charlie, f.weeks[0], week1, f.teams[1])
+	assert.NoError(t, GradeGame(db, &week1))
+
+	// Week 2: alice wins, bob loses
+	week2 := finalGame(t, db, f.weeks[1], f.teams[2], f.teams[3], 10, 17)
+	pick(db, f, alice, f.weeks[1], week2, f.teams[3])
+	pick(db, f, bob, f.weeks[1], week2, f.teams[2])
+	assert.NoError(t, GradeGame(db, &week2))
+
+	entrants, err := Standings(db, f.league.ID, f.season.ID)
+
+	assert.NoError(t, err)
+	assert.Len(t, entrants, 3)
+
+	// Alive entrants first
+	assert.Equal(t, "alice", entrants[0].Username)
+	assert.True(t, entrants[0].Alive)
+	assert.Nil(t, entrants[0].EliminatedWeekNumber)
+	assert.Equal(t, 2, entrants[0].WeeksPicked)
+
+	// Then the most recently eliminated
+	assert.Equal(t, "bob", entrants[1].Username)
+	assert.False(t, entrants[1].Alive)
+	assert.Equal(t, 2, *entrants[1].EliminatedWeekNumber)
+	assert.Equal(t, f.weeks[1].ID, *entrants[1].EliminatedWeekID)
+
+	assert.Equal(t, "charlie", entrants[2].Username)
+	assert.False(t, entrants[2].Alive)
+	assert.Equal(t, 1, *entrants[2].EliminatedWeekNumber)
+}
+
+func TestStandings_TeamReuseRejected(t *testing.T) {
+	db := setupTestDB(t)
+	f := seedSurvivor(t, db)
+
+	week1 := finalGame(t, db, f.weeks[0], f.teams[0], f.teams[1], 28, 21)
+	week2 := finalGame(t, db, f.weeks[1], f.teams[0], f.teams[2], 28, 21)
+	pick(db, f, f.users[0], f.weeks[0], week1, f.teams[0])
+
+	// The unique index prevents using the same team twice in a season
+	err := db.Create(&models.SurvivorPick{
+		LeagueID: f.league.ID,
+		UserID:   f.users[0].ID,
+		SeasonID: f.season.ID,
+		WeekID:   f.weeks[1].ID,
+		GameID:   week2.ID,
+		TeamID:   f.teams[0].ID,
+	}).Error
+	assert.Error(t, err)
+}
@@ -0,0 +1,72 @@
+package teammatch
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"gorm.io/gorm"
+)
+
+// Resolver matches team names from external feeds to teams
+// A name matches a team's name, abbreviation or any of its aliases, ignoring case, spaces and punctuation
+type Resolver struct {
+	byKey map[string]*models.Team
+}
+
+// NewResolver loads every team and its aliases
+func NewResolver(db *gorm.DB) (*Resolver, error) {
+	var teams []models.Team
+	if err := db.Preload("Aliases").Find(&teams).Error; err != nil {
+		return nil, err
+	}
+	return NewResolverForTeams(teams), nil
+}
+
+// NewResolverForTeams builds a resolver from already loaded teams
+// Names shared by more than one team are ambiguous and never match
+func NewResolverForTeams(teams []models.Team) *Resolver {
+	r := &Resolver{byKey: make(map[string]*models.Team)}
+	ambiguous := make(map[string]bool)
+
+	for i := range teams {
+		team := &teams[i]
+
+		names := []string{team.Name, team.Abbreviation}
+		for _, alias := range team.Aliases {
+			names = append(names, alias.Alias)
+		}
+
+		for _, name := range names {
+			key := Normalize(name)
+			if key == "" || ambiguous[key] {
+				continue
+			}
+			if existing, ok := r.byKey[key]; ok && existing.ID != team.ID {
+				delete(r.byKey, key)
+				ambiguous[key] = true
+				continue
+			}
+			r.byKey[key] = team
+		}
+	}
+
+	return r
+}
+
+// Resolve returns the team a name refers to
+func (r *Resolver) Resolve(name string) (*models.Team, bool) {
+	team, ok := r.byKey[Normalize(name)]
+	return team, ok
+}
+
+// Normalize reduces a team name to lowercase letters and digits so "Texas A&M" matches "texas a-m"
+func Normalize(name string) string {
+	var b strings.Builder
+	for _, c := range strings.ToLower(name) {
+		if unicode.IsLetter(c) || unicode.IsDigit(c) {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,41 @@
+package teammatch
+
+import (
+	"testing"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolve(t *testing.T) {
+	resolver := NewResolverForTeams([]models.Team{
+		{ID: 1, Name: "Texas A&M", Abbreviation: "TAMU"},
+		{ID: 2, Name: "Ole Miss", Abbreviation: "MISS", Aliases: []models.TeamAlias{{Alias: "Mississippi"}, {Alias: "Miami"}}},
+		{ID: 3, Name: "Miami", Abbreviation: "MIA"},
+	})
+
+	tests := []struct {
+		name   string
+		input  string
+		wantID uint
+		wantOK bool
+	}{
+		{"name", "Texas A&M", 1, true},
+		{"punctuation and case ignored", "texas a-m", 1, true},
+		{"abbreviation", "tamu", 1, true},
+		{"alias", "Mississippi", 2, true},
+		{"name shared with another team's alias is ambiguous", "Miami", 0, false},
+		{"unknown", "Vanderbilt", 0, false},
+		{"empty", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			team, ok := resolver.Resolve(tt.input)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.wantID, team.ID)
+			}
+		})
+	}
+}
@@ -14,6 +14,42 @@ func ValidateCreateGame(weekID, homeTeamID, awayTeamID uint, gameTime time.Time,
 		details["week_id"] = "Week ID is required"
 	}
 
+	validateGameFields(details, homeTeamID, awayTeamID, gameTime, homeSpread, total)
+
+	if len(details) > 0 {
+		return NewValidationError("Validation failed", details)
+	}
+
+	return nil
+}
+
+// ValidateScheduledGame validates a game on the shared master schedule
+func ValidateScheduledGame(year, weekNumber int, homeTeamID, awayTeamID uint, gameTime time.Time, homeSpread, total float64) *ValidationError {
+	details := make(map[string]string)
+
+	// Validate year and week
+	if year == 0 {
+		details["year"] = "Year is required"
+	} else if year < 2000 || year > time.Now().Year()+10 {
+		details["year"] = "Year must be between 2000 and 10 years in the future"
+	}
+	if weekNumber <= 0 {
+		details["week_number"] = "Week number must be greater than 0"
+	} else if weekNumber > 20 {
+		details["week_number"] = "Week number must be less than or equal to 20"
+	}
+
+	validateGameFields(details, homeTeamID, awayTeamID, gameTime, homeSpread, total)
+
+	if len(details) > 0 {
+		return NewValidationError("Validation failed", details)
+	}
+
+	return nil
+}
+
+// validateGameFields checks the teams, kickoff and line shared by league and master schedule games
+func validateGameFields(details map[string]string, homeTeamID, awayTeamID uint, gameTime time.Time, homeSpread, total float64) {
 	// Validate team IDs
 	if homeTeamID == 0 {
 		details["home_team_id"] = "Home team ID is required"
@@ -43,12 +79,6 @@ func ValidateCreateGame(weekID, homeTeamID, awayTeamID uint, gameTime time.Time,
 	} else if total > 200 {
 		details["total"] = "Total must be less than or equal to 200"
 	}
-
-	if len(details) > 0 {
-		return NewValidationError("Validation failed", details)
-	}
-
-	return nil
 }
 
 // ValidateUpdateGameResult validates game result update
@@ -106,3 +136,32 @@ func ValidateGameTime(gameTimeStr string) (time.Time, *ValidationError) {
 
 	return gameTime, nil
 }
+
+// ValidateGameStatusTransition validates that a game can move from one status to another
+// Games only become final by having a final result submitted
+func ValidateGameStatusTransition(currentStatus, newStatus string) *ValidationError {
+	validTransitions := map[string][]string{
+		"scheduled":   {"in_progress", "postponed", "cancelled"},
+		"in_progress": {"scheduled", "postponed", "cancelled"},
+		"postponed":   {"scheduled", "cancelled"},
+		"final":       {}, // Corrected through the game result instead
+		"cancelled":   {}, // Picks have been voided
+	}
+
+	allowedTransitions, exists := validTransitions[currentStatus]
+	if !exists {
+		return NewValidationError("Invalid game status", map[string]string{
+			"status": fmt.Sprintf("Unknown game status: %s", currentStatus),
+		})
+	}
+
+	for _, allowed := range allowedTransitions {
+		if allowed == newStatus {
+			return nil
+		}
+	}
+
+	return NewValidationError("Invalid status transition", map[string]string{
+		"status": fmt.Sprintf("Cannot transition from %s to %s", currentStatus, newStatus),
+	})
+}
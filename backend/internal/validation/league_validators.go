@@ -0,0 +1,101 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidLeagueFormats lists the league formats a commissioner can choose
+var ValidLeagueFormats = []string{"standard", "confidence", "survivor"}
+
+// ValidPostponedPicks lists what a league can do with picks on a postponed game
+var ValidPostponedPicks = []string{"keep", "void"}
+
+// ValidAutoPicks lists how a league can fill in games a member didn't pick
+var ValidAutoPicks = []string{"none", "favorite", "home", "underdog", "random", "consensus"}
+
+// ValidTiebreakers lists the stats a league can break ties on
+var ValidTiebreakers = []string{"correct_picks", "spread_wins", "head_to_head", "earliest_submission", "total_guess"}
+
+// ValidRankingStyles lists how a league can rank users that stay tied
+var ValidRankingStyles = []string{"standard", "dense"}
+
+// ValidGuessRules lists how a league can compare total-points guesses on the tiebreaker game
+var ValidGuessRules = []string{"without_going_over", "closest"}
+
+// ValidateLeagueSettings validates commissioner-configured league format, scoring, schedule-change, auto-pick
+// and standings values. tiebreakers is a comma-separated list; it may be empty to leave ties shared
+func ValidateLeagueSettings(format, postponedPicks, autoPick, tiebreakers, rankingStyle, guessRule string, spreadPoints, overUnderPoints, pushPoints, upsetBonusPoints int) *ValidationError {
+	details := make(map[string]string)
+
+	if !contains(ValidLeagueFormats, format) {
+		details["format"] = fmt.Sprintf("Format must be one of: %s", strings.Join(ValidLeagueFormats, ", "))
+	}
+
+	if !contains(ValidPostponedPicks, postponedPicks) {
+		details["postponed_picks"] = fmt.Sprintf("Postponed picks must be one of: %s", strings.Join(ValidPostponedPicks, ", "))
+	}
+
+	if !contains(ValidAutoPicks, autoPick) {
+		details["auto_pick"] = fmt.Sprintf("Auto pick must be one of: %s", strings.Join(ValidAutoPicks, ", "))
+	}
+
+	seen := make(map[string]bool)
+	for _, tiebreaker := range SplitList(tiebreakers) {
+		if !contains(ValidTiebreakers, tiebreaker) {
+			details["tiebreakers"] = fmt.Sprintf("Tiebreakers must be from: %s", strings.Join(ValidTiebreakers, ", "))
+		} else if seen[tiebreaker] {
+			details["tiebreakers"] = fmt.Sprintf("Tiebreaker %s is listed more than once", tiebreaker)
+		}
+		seen[tiebreaker] = true
+	}
+
+	if !contains(ValidRankingStyles, rankingStyle) {
+		details["ranking_style"] = fmt.Sprintf("Ranking style must be one of: %s", strings.Join(ValidRankingStyles, ", "))
+	}
+
+	if !contains(ValidGuessRules, guessRule) {
+		details["guess_rule"] = fmt.Sprintf("Guess rule must be one of: %s", strings.Join(ValidGuessRules, ", "))
+	}
+
+	points := map[string]int{
+		"spread_points":      spreadPoints,
+		"over_under_points":  overUnderPoints,
+		"push_points":        pushPoints,
+		"upset_bonus_points": upsetBonusPoints,
+	}
+	for field, value := range points {
+		if value < 0 {
+			details[field] = "Points cannot be negative"
+		} else if value > 100 {
+			details[field] = "Points must be less than or equal to 100"
+		}
+	}
+
+	if len(details) > 0 {
+		return NewValidationError("Validation failed", details)
+	}
+
+	return nil
+}
+
+// SplitList splits a comma-separated setting into its trimmed, non-empty values
+func SplitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// contains reports whether value is in values
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+package validation
+
+import (
+	"fmt"
+)
+
+// ValidateConfidence validates a confidence-pool value
+// Values must be 1..gameCount and unique among the user's other picks for the week
+func ValidateConfidence(confidence, gameCount int, usedValues []int) *ValidationError {
+	if confidence < 1 || confidence > gameCount {
+		return NewValidationError("Validation failed", map[string]string{
+			"confidence": fmt.Sprintf("Confidence must be between 1 and %d", gameCount),
+		})
+	}
+
+	for _, used := range usedValues {
+		if used == confidence {
+			return NewValidationError("Validation failed", map[string]string{
+				"confidence": fmt.Sprintf("Confidence %d is already used on another game this week", confidence),
+			})
+		}
+	}
+
+	return nil
+}
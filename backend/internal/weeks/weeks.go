@@ -0,0 +1,196 @@
+package weeks
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/scoring"
+	"github.com/ckinger23/mountaintop/internal/validation"
+	"gorm.io/gorm"
+)
+
+// Transition sources
+const (
+	SourceUser      = "user"
+	SourceScheduler = "scheduler"
+	SourceCLI       = "cli"
+)
+
+// ErrStatusChanged is returned when a week's status changed underneath a transition
+// (e.g. an admin and the scheduler locking the same week at once)
+var ErrStatusChanged = errors.New("week status changed concurrently")
+
+// Actor identifies who or what performed a transition
+type Actor struct {
+	Source string
+	UserID *uint
+	Reason string
+}
+
+// UserActor returns an actor for a transition made by a user
+func UserActor(userID uint) Actor {
+	return Actor{Source: SourceUser, UserID: &userID}
+}
+
+// SchedulerActor returns an actor for a transition made by the background scheduler
+func SchedulerActor(reason string) Actor {
+	return Actor{Source: SourceScheduler, Reason: reason}
+}
+
+// CLIActor returns an actor for a change made by a command-line tool
+func CLIActor(reason string) Actor {
+	return Actor{Source: SourceCLI, Reason: reason}
+}
+
+// Transition moves a week to a new status and records who performed it
+// The status update only applies if the week is still in the status it was loaded with.
+// Returns a *validation.ValidationError for transitions that aren't allowed
+func Transition(tx *gorm.DB, week *models.Week, newStatus string, actor Actor) error {
+	if valErr := validation.ValidateWeekStatusTransition(week.Status, newStatus); valErr != nil {
+		return valErr
+	}
+	return apply(tx, week, newStatus, actor)
+}
+
+// Reopen moves a finished week back to 'scoring'
+// Only used when a corrected result leaves one of the week's games no longer final
+func Reopen(tx *gorm.DB, week *models.Week, actor Actor) error {
+	if week.Status != "finished" {
+		return validation.NewValidationError("Invalid status transition", map[string]string{
+			"status": fmt.Sprintf("Only finished weeks can be reopened, week is %s", week.Status),
+		})
+	}
+	return apply(tx, week, "scoring", actor)
+}
+
+// apply updates a week's status and records the transition
+func apply(tx *gorm.DB, week *models.Week, newStatus string, actor Actor) error {
+	result := tx.Model(&models.Week{}).
+		Where("id = ? AND status = ?", week.ID, week.Status).
+		Update("status", newStatus)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrStatusChanged
+	}
+
+	transition := models.WeekTransition{
+		WeekID:     week.ID,
+		FromStatus: week.Status,
+		ToStatus:   newStatus,
+		Source:     actor.Source,
+		UserID:     actor.UserID,
+		Reason:     actor.Reason,
+	}
+	if err := tx.Create(&transition).Error; err != nil {
+		return err
+	}
+
+	week.Status = newStatus
+	return nil
+}
+
+// GameLocked reports whether picks on a game can no longer be changed
+// Leagues that lock at kickoff lock each game at its GameTime; others lock every game at the week's PickDeadline
+func GameLocked(week *models.Week, game *models.Game, lockAtKickoff bool, now time.Time) bool {
+	if game.IsFinal || week.Status == "scoring" || week.Status == "finished" {
+		return true
+	}
+	switch game.Status {
+	case models.GameStatusInProgress, models.GameStatusPostponed, models.GameStatusCancelled:
+		return true
+	}
+	if lockAtKickoff {
+		return !now.Before(game.GameTime)
+	}
+	return week.PickDeadline != nil && now.After(*week.PickDeadline)
+}
+
+// PickVisible reports whether other users may see picks on a game
+// Picks are revealed once they're locked, or once the game has kicked off
+func PickVisible(week *models.Week, game *models.Game, lockAtKickoff bool, now time.Time) bool {
+	return GameLocked(week, game, lockAtKickoff, now) || !now.Before(game.GameTime)
+}
+
+// LockTime returns when a week stops accepting picks entirely
+// For leagues that lock at kickoff that's the last scheduled kickoff of the week, otherwise the PickDeadline.
+// week must have its Games loaded. ok is false if there's nothing to lock on
+func LockTime(week *models.Week, lockAtKickoff bool) (lockAt time.Time, ok bool) {
+	if !lockAtKickoff {
+		if week.PickDeadline == nil {
+			return time.Time{}, false
+		}
+		return *week.PickDeadline, true
+	}
+
+	for _, game := range week.Games {
+		// Games that won't be played this week don't hold the week open
+		if game.Status == models.GameStatusPostponed || game.Status == models.GameStatusCancelled {
+			continue
+		}
+		if !ok || game.GameTime.After(lockAt) {
+			lockAt = game.GameTime
+			ok = true
+		}
+	}
+	return lockAt, ok
+}
+
+// LockHook runs in the same transaction as a scheduled lock, after the week has moved to 'scoring'
+type LockHook func(tx *gorm.DB, week *models.Week, actor Actor) error
+
+// LockExpired moves every 'picking' week whose lock time has passed to 'scoring'
+// onLock, if set, runs for each week as it locks; an error rolls that week's lock back.
+// Safe to run repeatedly and at startup: weeks that were missed while the server was down are caught up
+func LockExpired(db *gorm.DB, now time.Time, onLock LockHook) ([]models.Week, error) {
+	// Lock times are compared in Go since SQLite stores times as text with their original offset
+	var picking []models.Week
+	if err := db.Where("status = ?", "picking").Preload("Season").Preload("Games").Find(&picking).Error; err != nil {
+		return nil, err
+	}
+
+	var locked []models.Week
+	for i := range picking {
+		week := &picking[i]
+
+		settings, err := scoring.LoadSettings(db, week.Season.LeagueID)
+		if err != nil {
+			return locked, err
+		}
+
+		lockAt, ok := LockTime(week, settings.LockAtKickoff)
+		if !ok || lockAt.After(now) {
+			continue
+		}
+		reason := fmt.Sprintf("pick deadline %s passed", lockAt.Format(time.RFC3339))
+		if settings.LockAtKickoff {
+			reason = fmt.Sprintf("last kickoff %s passed", lockAt.Format(time.RFC3339))
+		}
+
+		actor := SchedulerActor(reason)
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := Transition(tx, week, "scoring", actor); err != nil {
+				return err
+			}
+			if onLock == nil {
+				return nil
+			}
+			return onLock(tx, week, actor)
+		})
+		if errors.Is(err, ErrStatusChanged) {
+			continue // Someone else already moved it
+		}
+		if err != nil {
+			return locked, fmt.Errorf("failed to lock week %d: %w", week.ID, err)
+		}
+
+		log.Printf("Locked week %d (%s): %s", week.ID, week.Name, reason)
+		locked = append(locked, *week)
+	}
+
+	return locked, nil
+}
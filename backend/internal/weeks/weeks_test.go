@@ -0,0 +1,197 @@
+package weeks
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ckinger23/mountaintop/internal/models"
+	"github.com/ckinger23/mountaintop/internal/validation"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupTestDB creates an in-memory SQLite database for testing
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+
+	if err := db.AutoMigrate(&models.User{}, &models.Season{}, &models.Week{}, &models.Game{}, &models.LeagueSettings{}, &models.WeekTransition{}); err != nil {
+		t.Fatalf("Failed to migrate test database: %v", err)
+	}
+
+	return db
+}
+
+func TestLockExpired(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Now()
+
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	expired := models.Week{SeasonID: 1, WeekNumber: 1, Name: "Week 1", Status: "picking", PickDeadline: &past}
+	open := models.Week{SeasonID: 1, WeekNumber: 2, Name: "Week 2", Status: "picking", PickDeadline: &future}
+	noDeadline := models.Week{SeasonID: 1, WeekNumber: 3, Name: "Week 3", Status: "picking"}
+	creating := models.Week{SeasonID: 1, WeekNumber: 4, Name: "Week 4", Status: "creating", PickDeadline: &past}
+	for _, week := range []*models.Week{&expired, &open, &noDeadline, &creating} {
+		db.Create(week)
+	}
+
+	locked, err := LockExpired(db, now, nil)
+
+	assert.NoError(t, err)
+	if assert.Len(t, locked, 1) {
+		assert.Equal(t, expired.ID, locked[0].ID)
+	}
+
+	var statuses []string
+	db.Model(&models.Week{}).Order("week_number ASC").Pluck("status", &statuses)
+	assert.Equal(t, []string{"scoring", "picking", "picking", "creating"}, statuses)
+
+	// The scheduler is recorded as the actor
+	var transition models.WeekTransition
+	assert.NoError(t, db.Where("week_id = ?", expired.ID).First(&transition).Error)
+	assert.Equal(t, "picking", transition.FromStatus)
+	assert.Equal(t, "scoring", transition.ToStatus)
+	assert.Equal(t, SourceScheduler, transition.Source)
+	assert.Nil(t, transition.UserID)
+	assert.Contains(t, transition.Reason, "pick deadline")
+
+	// Running again is a no-op
+	locked, err = LockExpired(db, now, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, locked)
+
+	var count int64
+	db.Model(&models.WeekTransition{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestLockExpired_LockAtKickoff(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Now()
+
+	season := models.Season{LeagueID: 1, Year: 2025}
+	db.Create(&season)
+	settings := models.LeagueSettings{LeagueID: 1, Format: models.LeagueFormatStandard, LockAtKickoff: true}
+	db.Create(&settings)
+
+	// The pick deadline is ignored; the week locks after its last kickoff
+	past := now.Add(-time.Hour)
+	inProgress := models.Week{SeasonID: season.ID, WeekNumber: 1, Name: "Week 1", Status: "picking", PickDeadline: &past}
+	allKickedOff := models.Week{SeasonID: season.ID, WeekNumber: 2, Name: "Week 2", Status: "picking"}
+	for _, week := range []*models.Week{&inProgress, &allKickedOff} {
+		db.Create(week)
+	}
+	db.Create(&models.Game{WeekID: inProgress.ID, HomeTeamID: 1, AwayTeamID: 2, GameTime: now.Add(-2 * time.Hour)})
+	db.Create(&models.Game{WeekID: inProgress.ID, HomeTeamID: 3, AwayTeamID: 4, GameTime: now.Add(time.Hour)})
+	db.Create(&models.Game{WeekID: allKickedOff.ID, HomeTeamID: 1, AwayTeamID: 2, GameTime: now.Add(-2 * time.Hour)})
+
+	locked, err := LockExpired(db, now, nil)
+
+	assert.NoError(t, err)
+	if assert.Len(t, locked, 1) {
+		assert.Equal(t, allKickedOff.ID, locked[0].ID)
+	}
+
+	var transition models.WeekTransition
+	assert.NoError(t, db.Where("week_id = ?", allKickedOff.ID).First(&transition).Error)
+	assert.Contains(t, transition.Reason, "last kickoff")
+}
+
+func TestLockExpired_Hook(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Now()
+
+	past := now.Add(-time.Hour)
+	week := models.Week{SeasonID: 1, WeekNumber: 1, Name: "Week 1", Status: "picking", PickDeadline: &past}
+	db.Create(&week)
+
+	// A failing hook rolls the lock back so the next run tries again
+	_, err := LockExpired(db, now, func(tx *gorm.DB, week *models.Week, actor Actor) error {
+		return errors.New("auto-pick failed")
+	})
+	assert.Error(t, err)
+	db.First(&week, week.ID)
+	assert.Equal(t, "picking", week.Status)
+
+	var hooked []uint
+	locked, err := LockExpired(db, now, func(tx *gorm.DB, week *models.Week, actor Actor) error {
+		assert.Equal(t, "scoring", week.Status)
+		assert.Equal(t, SourceScheduler, actor.Source)
+		hooked = append(hooked, week.ID)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, locked, 1)
+	assert.Equal(t, []uint{week.ID}, hooked)
+}
+
+func TestGameLocked(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	open := &models.Week{Status: "picking", PickDeadline: &future}
+	deadlinePassed := &models.Week{Status: "picking", PickDeadline: &past}
+	scoringWeek := &models.Week{Status: "scoring", PickDeadline: &future}
+
+	notStarted := &models.Game{GameTime: future}
+	kickedOff := &models.Game{GameTime: past}
+
+	tests := []struct {
+		name          string
+		week          *models.Week
+		game          *models.Game
+		lockAtKickoff bool
+		wantLocked    bool
+		wantVisible   bool
+	}{
+		{"deadline: open week", open, notStarted, false, false, false},
+		{"deadline: kicked off before deadline stays open but visible", open, kickedOff, false, false, true},
+		{"deadline: passed", deadlinePassed, notStarted, false, true, true},
+		{"kickoff: not started", open, notStarted, true, false, false},
+		{"kickoff: ignores passed deadline", deadlinePassed, notStarted, true, false, false},
+		{"kickoff: kicked off", open, kickedOff, true, true, true},
+		{"scoring week is locked", scoringWeek, notStarted, true, true, true},
+		{"final game is locked", open, &models.Game{GameTime: future, IsFinal: true}, false, true, true},
+		{"cancelled game is locked", open, &models.Game{GameTime: future, Status: models.GameStatusCancelled}, false, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantLocked, GameLocked(tt.week, tt.game, tt.lockAtKickoff, now))
+			assert.Equal(t, tt.wantVisible, PickVisible(tt.week, tt.game, tt.lockAtKickoff, now))
+		})
+	}
+}
+
+func TestTransition(t *testing.T) {
+	db := setupTestDB(t)
+
+	week := models.Week{SeasonID: 1, WeekNumber: 1, Name: "Week 1", Status: "picking"}
+	db.Create(&week)
+
+	// Invalid transitions are rejected with a validation error
+	err := Transition(db, &week, "finished", UserActor(7))
+	var valErr *validation.ValidationError
+	assert.ErrorAs(t, err, &valErr)
+	assert.Equal(t, "picking", week.Status)
+
+	// A stale copy can't transition once another actor has moved the week
+	stale := week
+	assert.NoError(t, Transition(db, &week, "scoring", UserActor(7)))
+	assert.Equal(t, "scoring", week.Status)
+	assert.ErrorIs(t, Transition(db, &stale, "scoring", SchedulerActor("deadline")), ErrStatusChanged)
+
+	var transitions []models.WeekTransition
+	db.Find(&transitions)
+	if assert.Len(t, transitions, 1) {
+		assert.Equal(t, SourceUser, transitions[0].Source)
+		assert.Equal(t, uint(7), *transitions[0].UserID)
+	}
+}
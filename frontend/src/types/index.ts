@@ -37,6 +37,13 @@ export interface Team {
   abbreviation: string;
   logo_url?: string;
   conference: string;
+  aliases?: TeamAlias[];
+}
+
+export interface TeamAlias {
+  id: number;
+  team_id: number;
+  alias: string;
 }
 
 export interface Season {
@@ -57,9 +64,13 @@ export interface Week {
   name: string;
   status: WeekStatus;
   pick_deadline?: string;
+  is_postseason: boolean;
+  tiebreaker_game_id?: number; // Game whose combined score members guess to break ties
   season?: Season;
 }
 
+export type GameStatus = 'scheduled' | 'in_progress' | 'final' | 'postponed' | 'cancelled';
+
 export interface Game {
   id: number;
   week_id: number;
@@ -68,15 +79,41 @@ export interface Game {
   game_time: string;
   home_spread: number;
   total: number;
+  status: GameStatus;
   is_final: boolean;
   home_score?: number;
   away_score?: number;
   winner_team_id?: number;
+  period: number;
+  clock: string;
+  external_id?: string;
+  scheduled_game_id?: number; // Set when selected from the master schedule
   home_team: Team;
   away_team: Team;
   week?: Week;
 }
 
+// A real-world game on the shared master schedule
+export interface ScheduledGame {
+  id: number;
+  year: number;
+  week_number: number;
+  home_team_id: number;
+  away_team_id: number;
+  game_time: string;
+  home_spread: number;
+  total: number;
+  status: GameStatus;
+  external_id?: string;
+  is_final: boolean;
+  home_score?: number;
+  away_score?: number;
+  home_team: Team;
+  away_team: Team;
+}
+
+export type PickOutcome = '' | 'win' | 'loss' | 'push' | 'void';
+
 export interface Pick {
   id: number;
   league_id: number;
@@ -85,15 +122,63 @@ export interface Pick {
   picked_team_id: number;
   picked_over_under: string; // "over" or "under"
   confidence?: number;
-  spread_correct?: boolean;
-  over_under_correct?: boolean;
+  home_spread?: number; // Line when the pick was made
+  total?: number;
+  spread_outcome?: PickOutcome;
+  over_under_outcome?: PickOutcome;
   points_earned: number;
+  commissioner_entered: boolean; // Entered or last changed by the commissioner for the member
+  entered_by_id?: number;
+  auto_picked: boolean; // Filled in by the league's auto-pick policy for a missed game
   game: Game;
   picked_team: Team;
   user?: User;
   league?: League;
 }
 
+export interface PickChange {
+  id: number;
+  created_at: string;
+  pick_id: number;
+  league_id: number;
+  user_id: number;
+  game_id: number;
+  action: 'create' | 'update';
+  old_picked_team_id: number | null; // Old values are null for creates
+  old_picked_over_under: string | null;
+  old_confidence: number | null;
+  new_picked_team_id: number;
+  new_picked_over_under: string;
+  new_confidence: number;
+  source: string;
+  actor_id: number | null;
+  reason: string;
+  ip?: string; // Only in a week's pick history, which only the commissioner sees
+  override: boolean; // Made by the commissioner on the member's behalf
+  user?: User;
+  actor?: User;
+}
+
+export interface PickLeagueResult {
+  league_id: number;
+  game_id: number;
+  accepted: boolean;
+  pick?: Pick;
+  code?: string; // Why the league rejected the pick
+  error?: string;
+}
+
+export interface GameLine {
+  id: number;
+  created_at: string;
+  game_id: number;
+  home_spread: number;
+  total: number;
+  source: string;
+  user_id?: number;
+  reason?: string;
+}
+
 export interface LeaderboardEntry {
   league_id?: number;
   user_id: number;
@@ -101,8 +186,58 @@ export interface LeaderboardEntry {
   display_name: string;
   total_points: number;
   correct_picks: number;
+  push_picks: number;
   total_picks: number;
   win_pct: number;
+  rank: number; // Shared by users still tied after every tiebreaker
+  spread_wins: number;
+  head_to_head_wins: number;
+  first_pick_at?: string;
+  total_guess?: number; // Guess on the tiebreaker game of the latest week it's final for
+  guess_miss?: number;
+  guess_over?: boolean;
+}
+
+export interface WeekScore {
+  week_id: number;
+  user_id: number;
+  points: number;
+  correct_picks: number;
+  spread_wins: number;
+  total_picks: number;
+  total_guess?: number; // Set once the week's tiebreaker game is final
+  guess_miss?: number;
+  guess_over?: boolean;
+}
+
+export interface TiebreakerGuess {
+  id: number;
+  league_id: number;
+  user_id: number;
+  week_id: number;
+  total_points: number;
+}
+
+export interface WeekResult {
+  week_id: number;
+  week_number: number;
+  name: string;
+  status: string;
+  final: boolean; // Week is finished, so its winners won't change
+  winners: number[]; // User IDs; empty until a game is final, more than one when the week is shared
+}
+
+export interface WeeklyGrid {
+  league_id: number;
+  season_id: number;
+  weeks: WeekResult[];
+  rows: {
+    user_id: number;
+    username: string;
+    display_name: string;
+    total_points: number;
+    weeks: WeekScore[]; // Lines up with the grid's weeks
+  }[];
 }
 
 export interface AuthResponse {